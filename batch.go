@@ -0,0 +1,185 @@
+package placekey
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// streamChunkSize is the number of values the stream functions convert at a time.
+const streamChunkSize int = 4096
+
+// LatLng is a (latitude, longitude) coordinate.
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// GeoResult is the result of converting a Placekey into a (latitude, longitude).
+type GeoResult struct {
+	LatLng LatLng
+	Err    error
+}
+
+// FromGeoBatch converts a slice of (latitude, longitude) coordinates into Placekeys in parallel,
+// using up to workers goroutines, or runtime.GOMAXPROCS(0) if workers is less than 1.
+func FromGeoBatch(latlngs []LatLng, workers int) []string {
+	placekeys := make([]string, len(latlngs))
+	fromGeoInto(placekeys, latlngs, workers)
+	return placekeys
+}
+
+// ToGeoBatch converts a slice of Placekeys into (latitude, longitude) coordinates in parallel,
+// using up to workers goroutines, or runtime.GOMAXPROCS(0) if workers is less than 1. The error
+// at each index is non-nil if the Placekey at that index is invalid.
+func ToGeoBatch(placekeys []string, workers int) ([]LatLng, []error) {
+	latlngs := make([]LatLng, len(placekeys))
+	errs := make([]error, len(placekeys))
+	toGeoInto(latlngs, errs, placekeys, workers)
+	return latlngs, errs
+}
+
+// FromGeoStream converts (latitude, longitude) coordinates received on a channel into Placekeys,
+// converting them in parallel chunks as FromGeoBatch does and sending them in the order they
+// were received. The returned channel is closed after the input channel is closed or ctx is
+// done, so cancel ctx to stop the stream before reading all of its output.
+func FromGeoStream(ctx context.Context, in <-chan LatLng, workers int) <-chan string {
+	out := make(chan string, streamChunkSize)
+	go func() {
+		defer close(out)
+		latlngs := make([]LatLng, streamChunkSize)
+		placekeys := make([]string, streamChunkSize)
+		stream(func(i int, wait bool) (ok bool) {
+			if wait {
+				select {
+				case latlngs[i], ok = <-in:
+				case <-ctx.Done():
+				}
+				return ok
+			}
+			select {
+			case latlngs[i], ok = <-in:
+			default:
+			}
+			return ok
+		}, func(n int) bool {
+			fromGeoInto(placekeys[:n], latlngs[:n], workers)
+			for _, pk := range placekeys[:n] {
+				select {
+				case out <- pk:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		})
+	}()
+	return out
+}
+
+// ToGeoStream converts Placekeys received on a channel into (latitude, longitude) coordinates,
+// converting them in parallel chunks as ToGeoBatch does and sending them in the order they were
+// received. The returned channel is closed after the input channel is closed or ctx is done, so
+// cancel ctx to stop the stream before reading all of its output.
+func ToGeoStream(ctx context.Context, in <-chan string, workers int) <-chan GeoResult {
+	out := make(chan GeoResult, streamChunkSize)
+	go func() {
+		defer close(out)
+		placekeys := make([]string, streamChunkSize)
+		latlngs := make([]LatLng, streamChunkSize)
+		errs := make([]error, streamChunkSize)
+		stream(func(i int, wait bool) (ok bool) {
+			if wait {
+				select {
+				case placekeys[i], ok = <-in:
+				case <-ctx.Done():
+				}
+				return ok
+			}
+			select {
+			case placekeys[i], ok = <-in:
+			default:
+			}
+			return ok
+		}, func(n int) bool {
+			toGeoInto(latlngs[:n], errs[:n], placekeys[:n], workers)
+			for i := range placekeys[:n] {
+				select {
+				case out <- GeoResult{LatLng: latlngs[i], Err: errs[i]}:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		})
+	}()
+	return out
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+func fromGeoInto(placekeys []string, latlngs []LatLng, workers int) {
+	parallel(len(latlngs), workers, func(start, end int) {
+		for i := start; i < end; i++ {
+			placekeys[i] = FromGeo(latlngs[i].Lat, latlngs[i].Lng)
+		}
+	})
+}
+
+func toGeoInto(latlngs []LatLng, errs []error, placekeys []string, workers int) {
+	parallel(len(placekeys), workers, func(start, end int) {
+		for i := start; i < end; i++ {
+			lat, lng, err := ToGeoE(placekeys[i])
+			latlngs[i] = LatLng{Lat: lat, Lng: lng}
+			errs[i] = err
+		}
+	})
+}
+
+// parallel splits the range [0, n) into contiguous blocks and calls f on each from its own
+// goroutine, using up to workers goroutines, or runtime.GOMAXPROCS(0) if workers is less than 1.
+func parallel(n, workers int, f func(start, end int)) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		f(0, n)
+		return
+	}
+
+	size := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			f(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// stream receives values into chunks of up to streamChunkSize and hands each chunk to send,
+// until receive gets nothing while waiting or send returns false. receive stores the value it
+// receives at index i of the chunk and reports whether it received one, waiting for it if wait
+// is true. A chunk ends when it is full or no value is ready after at least one has been
+// received.
+func stream(receive func(i int, wait bool) bool, send func(n int) bool) {
+	for {
+		n := 0
+		for n < streamChunkSize && receive(n, n == 0) {
+			n++
+		}
+		if n == 0 || !send(n) {
+			return
+		}
+	}
+}
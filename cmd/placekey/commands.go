@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/engelsjk/placekey-go"
+)
+
+func fromGeo(fields []string) (string, error) {
+	lat, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", err
+	}
+	lon, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return "", err
+	}
+	return placekey.FromGeoE(lat, lon)
+}
+
+func toGeo(fields []string) (string, error) {
+	lat, lon, err := placekey.ToGeoE(fields[0])
+	if err != nil {
+		return "", err
+	}
+	return formatFloat(lat) + "," + formatFloat(lon), nil
+}
+
+func toH3(fields []string) (string, error) {
+	return placekey.ToH3E(fields[0])
+}
+
+func fromH3(fields []string) (string, error) {
+	return placekey.FromH3E(fields[0])
+}
+
+func validate(fields []string) (string, error) {
+	if _, err := placekey.ParseWhere(fields[0]); err != nil {
+		return "", err
+	}
+	return fields[0], nil
+}
+
+func distance(fields []string) (string, error) {
+	d, err := placekey.DistanceE(fields[0], fields[1])
+	if err != nil {
+		return "", err
+	}
+	return formatFloat(d), nil
+}
+
+// boundaryFormat is the --format flag of boundary. It rejects unknown formats when the flags are
+// parsed, so they are a usage error rather than a failure of every input.
+type boundaryFormat string
+
+func (f *boundaryFormat) String() string {
+	return string(*f)
+}
+
+func (f *boundaryFormat) Set(s string) error {
+	if s != "geojson" && s != "wkt" {
+		return fmt.Errorf("unknown format %q", s)
+	}
+	*f = boundaryFormat(s)
+	return nil
+}
+
+func boundary(fields []string, format string) (string, error) {
+	switch format {
+	case "geojson":
+		return placekey.ToGeoJSONE(fields[0])
+	case "wkt":
+		return placekey.ToWKTE(fields[0])
+	default:
+		return "", fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
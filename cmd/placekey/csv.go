@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/engelsjk/placekey-go/csv"
+)
+
+// runCSV enriches a CSV file, or stdin if no file is given, and writes it to stdout.
+func runCSV(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := csv.DefaultOptions()
+
+	fs := flag.NewFlagSet("csv", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.LatColumn, "lat", opts.LatColumn, "latitude column")
+	fs.StringVar(&opts.LonColumn, "lon", opts.LonColumn, "longitude column")
+	fs.StringVar(&opts.H3Column, "h3", "", "H3 column to read instead of latitude and longitude")
+	fs.StringVar(&opts.PlacekeyColumn, "placekey", opts.PlacekeyColumn, "appended Placekey column, or empty to skip")
+	fs.StringVar(&opts.H3OutColumn, "h3-out", "", "appended H3 column")
+	fs.StringVar(&opts.WKTColumn, "wkt", "", "appended boundary WKT column")
+	rejectsPath := fs.String("rejects", "", "file to write rejected rows to")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintln(stderr, "placekey csv: expected at most one input file")
+		return 2
+	}
+
+	in := stdin
+	if fs.NArg() == 1 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(stderr, "placekey csv: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var rejects io.Writer
+	if *rejectsPath != "" {
+		f, err := os.Create(*rejectsPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "placekey csv: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		rejects = f
+	}
+
+	stats, err := csv.Enrich(in, stdout, rejects, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "placekey csv: %v\n", err)
+		return 1
+	}
+	if stats.Rejected > 0 {
+		fmt.Fprintf(stderr, "placekey csv: rejected %d of %d rows\n", stats.Rejected, stats.Rows)
+		return 1
+	}
+	return 0
+}
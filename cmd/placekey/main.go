@@ -0,0 +1,145 @@
+// Command placekey converts between Placekeys, coordinates, H3 cells and boundaries.
+//
+// Each command takes its inputs as arguments or, when no arguments are given, reads one
+// input per line from stdin, so it can be used in shell pipelines:
+//
+//	placekey from-geo 37.7371 -122.44283
+//	cut -d, -f1,2 points.csv | placekey from-geo
+//	placekey boundary --format wkt @5vg-82n-kzz
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const usage = `usage: placekey <command> [flags] [inputs...]
+
+commands:
+  from-geo <lat> <lon>           convert a (latitude, longitude) into a Placekey
+  to-geo <placekey>              convert a Placekey into a (latitude, longitude)
+  to-h3 <placekey>               convert a Placekey into an H3 string
+  from-h3 <h3>                   convert an H3 string into a Placekey
+  validate <placekey>            print the Placekey if its format is valid
+  distance <placekey> <placekey> distance in meters between two Placekeys
+  boundary <placekey>            boundary of a Placekey (--format geojson|wkt)
+  csv [file]                     append Placekey columns to a CSV (see csv -h)
+
+When no inputs are given, each line of stdin is read as one input, with fields
+separated by commas or whitespace. Use -- before a negative latitude.
+`
+
+// a command converts the fields of one input into one line of output.
+type command struct {
+	nfields int
+	convert func(fields []string) (string, error)
+}
+
+// newCommand returns the named command, registering its flags on fs.
+func newCommand(name string, fs *flag.FlagSet) (command, bool) {
+	switch name {
+	case "from-geo":
+		return command{2, fromGeo}, true
+	case "to-geo":
+		return command{1, toGeo}, true
+	case "to-h3":
+		return command{1, toH3}, true
+	case "from-h3":
+		return command{1, fromH3}, true
+	case "validate":
+		return command{1, validate}, true
+	case "distance":
+		return command{2, distance}, true
+	case "boundary":
+		format := boundaryFormat("geojson")
+		fs.Var(&format, "format", "output `format`: geojson or wkt")
+		return command{1, func(fields []string) (string, error) {
+			return boundary(fields, string(format))
+		}}, true
+	}
+	return command{}, false
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes a command and returns the exit code: 0 on success, 1 if any input failed
+// and 2 on a usage error.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	if args[0] == "csv" {
+		return runCSV(args[1:], stdin, stdout, stderr)
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cmd, ok := newCommand(args[0], fs)
+	if !ok {
+		fmt.Fprintf(stderr, "placekey: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+
+	failed := false
+	each := func(fields []string) {
+		if len(fields) != cmd.nfields {
+			fmt.Fprintf(stderr, "placekey %s: expected %d fields, got %q\n", args[0], cmd.nfields, strings.Join(fields, " "))
+			failed = true
+			return
+		}
+		out, err := cmd.convert(fields)
+		if err != nil {
+			fmt.Fprintf(stderr, "placekey %s: %v\n", args[0], err)
+			failed = true
+			return
+		}
+		fmt.Fprintln(w, out)
+	}
+
+	if inputs := fs.Args(); len(inputs) > 0 {
+		if len(inputs)%cmd.nfields != 0 {
+			fmt.Fprintf(stderr, "placekey %s: expected inputs of %d fields\n", args[0], cmd.nfields)
+			return 2
+		}
+		for i := 0; i < len(inputs); i += cmd.nfields {
+			each(inputs[i : i+cmd.nfields])
+		}
+	} else {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			each(splitFields(line))
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintf(stderr, "placekey %s: %v\n", args[0], err)
+			failed = true
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+func splitFields(line string) []string {
+	return strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args  []string
+		stdin string
+		want  string
+		code  int
+	}{
+		{[]string{"from-geo", "37.7371", "-122.44283"}, "", "@5vg-82n-kzz\n", 0},
+		{[]string{"from-geo"}, "37.7371,-122.44283\n\n37.7371 -122.44283\n", "@5vg-82n-kzz\n@5vg-82n-kzz\n", 0},
+		{[]string{"to-h3", "@dvt-smp-tvz"}, "", "8a754e64992ffff\n", 0},
+		{[]string{"from-h3"}, "8a754e64992ffff\n", "@dvt-smp-tvz\n", 0},
+		{[]string{"validate"}, "222-227@dvt-smp-tvz\n@123-456-789\n", "222-227@dvt-smp-tvz\n", 1},
+		{[]string{"distance", "@dvt-smp-tvz", "@dvt-smp-tvz"}, "", "0\n", 0},
+		{[]string{"boundary", "--format", "wkt", "@5vg-82n-kzz"}, "", "POLYGON((", 0},
+		{[]string{"boundary", "--format", "kml", "@5vg-82n-kzz"}, "", "", 2},
+		{[]string{"boundary", "--format", "kml"}, "@5vg-82n-kzz\n@5vg-82n-k9f\n", "", 2},
+		{[]string{"from-geo", "37.7371"}, "", "", 2},
+		{[]string{"csv", "--h3-out", "h3"}, "latitude,longitude\n37.7371,-122.44283\n", "latitude,longitude,placekey,h3\n37.7371,-122.44283,@5vg-82n-kzz,8a2830953157fff\n", 0},
+		{[]string{"unknown"}, "", "", 2},
+	}
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		code := run(test.args, strings.NewReader(test.stdin), &stdout, &stderr)
+		if code != test.code || !strings.HasPrefix(stdout.String(), test.want) {
+			t.Errorf(`run(%q) = %d, "%s"; wanted %d, "%s"`, test.args, code, stdout.String(), test.code, test.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package placekey
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// The pyEncode and pyDecode functions below are line-for-line transcriptions of
+// _encode_short_int, _clean_string, _dirty_string and _decode_string in placekey-py, kept
+// deliberately naive so that the optimized encoder can be checked against them.
+
+var pyReplacementMap = [][2]string{
+	{"prn", "pre"},
+	{"f4nny", "f4nne"},
+	{"tw4t", "tw4e"},
+	{"ngr", "ngu"},
+	{"dck", "dce"},
+	{"vjn", "vju"},
+	{"fck", "fce"},
+	{"pns", "pne"},
+	{"sht", "she"},
+	{"kkk", "kke"},
+	{"fgt", "fgu"},
+	{"dyk", "dye"},
+	{"bch", "bce"},
+}
+
+func pyEncode(h3Int uint64) string {
+	x := shortenH3Int(h3Int)
+	s := ""
+	if x == 0 {
+		s = string(alphabet[0])
+	}
+	for x > 0 {
+		s = string(alphabet[x%28]) + s
+		x /= 28
+	}
+	for _, kv := range pyReplacementMap {
+		if strings.Contains(s, kv[0]) {
+			s = strings.Replace(s, kv[0], kv[1], -1)
+		}
+	}
+	s = strings.Repeat("a", 9-len(s)) + s
+	return "@" + s[0:3] + "-" + s[3:6] + "-" + s[6:9]
+}
+
+func pyDecode(placekey string) uint64 {
+	s := placekey[strings.Index(placekey, "@")+1:]
+	s = strings.Replace(s, "-", "", -1)
+	s = strings.Replace(s, "a", "", -1)
+	for i := len(pyReplacementMap) - 1; i >= 0; i-- {
+		kv := pyReplacementMap[i]
+		if strings.Contains(s, kv[1]) {
+			s = strings.Replace(s, kv[1], kv[0], -1)
+		}
+	}
+	var x uint64
+	for _, c := range s {
+		x = x*28 + uint64(strings.IndexRune(alphabet, c))
+	}
+	return unshortenH3Int(x)
+}
+
+// conformanceCorpus returns H3 integers covering every base cell, a seeded random sample of
+// the globe and codes built to contain every replacement pattern and every overlapping pair.
+func conformanceCorpus() []uint64 {
+	corpus := []uint64{}
+
+	for bc := uint64(0); bc < 122; bc++ {
+		center := h3Indexer.ToGeo(0x8001fffffffffff | bc<<45)
+		corpus = append(corpus, h3Indexer.FromGeo(center.Lat, center.Lng, resolution))
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		lat := r.Float64()*180 - 90
+		lon := r.Float64()*360 - 180
+		corpus = append(corpus, h3Indexer.FromGeo(lat, lon, resolution))
+	}
+
+	words := []string{}
+	for _, kv := range pyReplacementMap {
+		words = append(words, kv[0])
+	}
+	patterns := append([]string{}, words...)
+	for _, p := range words {
+		for _, q := range words {
+			patterns = append(patterns, p+q)
+			for k := 1; k < len(p) && k < len(q); k++ {
+				if p[len(p)-k:] == q[:k] {
+					patterns = append(patterns, p+q[k:])
+				}
+			}
+		}
+	}
+	for _, p := range patterns {
+		if len(p) > 9 {
+			continue
+		}
+		for offset := 0; offset+len(p) <= 9; offset++ {
+			for _, fill := range []string{"2", "z", "k"} {
+				code := strings.Repeat(fill, offset) + p + strings.Repeat(fill, 9-offset-len(p))
+				var x uint64
+				for _, c := range code {
+					x = x*28 + uint64(strings.IndexRune(alphabet, c))
+				}
+				corpus = append(corpus, unshortenH3Int(x))
+			}
+		}
+	}
+
+	return corpus
+}
+
+func TestConformance(t *testing.T) {
+	if ReplacementVersion != 1 {
+		t.Fatalf(`ReplacementVersion = %d; the reference transcription covers version 1`, ReplacementVersion)
+	}
+	failures := 0
+	for _, h3Int := range conformanceCorpus() {
+		pk := pyEncode(h3Int)
+		if got := FromH3Int(h3Int); got != pk {
+			t.Errorf(`FromH3Int(%x) = "%s"; placekey-py encodes "%s"`, h3Int, got, pk)
+			failures++
+		}
+		if got, want := ToH3Int(pk), pyDecode(pk); got != want {
+			t.Errorf(`ToH3Int("%s") = %x; placekey-py decodes %x`, pk, got, want)
+			failures++
+		}
+		if failures > 10 {
+			t.Fatal("too many failures")
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Package csv adds Placekey columns to CSV files of coordinates or H3 cells.
+//
+// Rows are streamed one at a time, so files of any size are enriched in constant memory.
+// Rows that can't be converted are written to a separate rejects writer along with the reason.
+package csv
+
+import (
+	stdcsv "encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/engelsjk/placekey-go"
+)
+
+// Options configures the columns read from and appended to each row.
+type Options struct {
+	// LatColumn and LonColumn name the coordinate columns. They are ignored if H3Column is set.
+	LatColumn string
+	LonColumn string
+	// H3Column names a column of H3 strings to read instead of coordinates.
+	H3Column string
+
+	// PlacekeyColumn, H3OutColumn and WKTColumn name the appended Placekey, H3 and boundary WKT
+	// columns. A column with an empty name is not appended.
+	PlacekeyColumn string
+	H3OutColumn    string
+	WKTColumn      string
+
+	// Comma is the field delimiter of both the input and the output.
+	Comma rune
+}
+
+// DefaultOptions returns Options that read "latitude" and "longitude" columns and append a
+// "placekey" column.
+func DefaultOptions() Options {
+	return Options{
+		LatColumn:      "latitude",
+		LonColumn:      "longitude",
+		PlacekeyColumn: "placekey",
+		Comma:          ',',
+	}
+}
+
+// Stats counts the rows processed by Enrich.
+type Stats struct {
+	Rows     int
+	Rejected int
+}
+
+// Enrich reads a CSV with a header row from r and writes it to w with the configured columns
+// appended, keeping the original columns in order. Rows that can't be converted are written
+// to rejects with an appended "error" column; rejects may be nil to drop them.
+func Enrich(r io.Reader, w io.Writer, rejects io.Writer, opts Options) (Stats, error) {
+	stats := Stats{}
+
+	if opts.Comma == 0 {
+		opts.Comma = ','
+	}
+	if opts.PlacekeyColumn == "" && opts.H3OutColumn == "" && opts.WKTColumn == "" {
+		return stats, errors.New("csv: no output columns")
+	}
+
+	reader := stdcsv.NewReader(r)
+	reader.Comma = opts.Comma
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	writer := stdcsv.NewWriter(w)
+	writer.Comma = opts.Comma
+
+	var rejectWriter *stdcsv.Writer
+	if rejects != nil {
+		rejectWriter = stdcsv.NewWriter(rejects)
+		rejectWriter.Comma = opts.Comma
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, err
+	}
+
+	loc, err := newLocator(header, opts)
+	if err != nil {
+		return stats, err
+	}
+
+	out := append([]string{}, header...)
+	for _, name := range []string{opts.PlacekeyColumn, opts.H3OutColumn, opts.WKTColumn} {
+		if name != "" {
+			out = append(out, name)
+		}
+	}
+	if err := writer.Write(out); err != nil {
+		return stats, err
+	}
+	if rejectWriter != nil {
+		if err := rejectWriter.Write(append(append([]string{}, header...), "error")); err != nil {
+			return stats, err
+		}
+	}
+
+	reject := func(record []string, reason error) error {
+		stats.Rejected++
+		if rejectWriter == nil {
+			return nil
+		}
+		return rejectWriter.Write(append(record, reason.Error()))
+	}
+
+	row := make([]string, 0, len(out))
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		stats.Rows++
+		if err != nil {
+			var parseErr *stdcsv.ParseError
+			if !errors.As(err, &parseErr) {
+				return stats, err
+			}
+			if err := reject(record, err); err != nil {
+				return stats, err
+			}
+			continue
+		}
+
+		pk, err := loc.placekey(record)
+		if err != nil {
+			if err := reject(record, err); err != nil {
+				return stats, err
+			}
+			continue
+		}
+
+		row, err = appendColumns(append(row[:0], record...), pk, opts)
+		if err != nil {
+			return stats, err
+		}
+		if err := writer.Write(row); err != nil {
+			return stats, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return stats, err
+	}
+	if rejectWriter != nil {
+		rejectWriter.Flush()
+		if err := rejectWriter.Error(); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+var errMissingColumn = errors.New("missing column")
+
+// locator finds the Placekey of a row from its coordinate or H3 columns.
+type locator struct {
+	lat, lon, h3 int
+}
+
+func newLocator(header []string, opts Options) (locator, error) {
+	loc := locator{lat: -1, lon: -1, h3: -1}
+	if opts.H3Column != "" {
+		loc.h3 = columnIndex(header, opts.H3Column)
+		if loc.h3 < 0 {
+			return loc, fmt.Errorf("csv: column %q not found", opts.H3Column)
+		}
+		return loc, nil
+	}
+	for _, c := range []struct {
+		name string
+		i    *int
+	}{{opts.LatColumn, &loc.lat}, {opts.LonColumn, &loc.lon}} {
+		*c.i = columnIndex(header, c.name)
+		if *c.i < 0 {
+			return loc, fmt.Errorf("csv: column %q not found", c.name)
+		}
+	}
+	return loc, nil
+}
+
+func (loc locator) placekey(record []string) (string, error) {
+	if loc.h3 >= 0 {
+		if loc.h3 >= len(record) {
+			return "", errMissingColumn
+		}
+		return placekey.FromH3E(record[loc.h3])
+	}
+	if loc.lat >= len(record) || loc.lon >= len(record) {
+		return "", errMissingColumn
+	}
+	lat, err := strconv.ParseFloat(record[loc.lat], 64)
+	if err != nil {
+		return "", err
+	}
+	lon, err := strconv.ParseFloat(record[loc.lon], 64)
+	if err != nil {
+		return "", err
+	}
+	return placekey.FromGeoE(lat, lon)
+}
+
+func appendColumns(row []string, pk string, opts Options) ([]string, error) {
+	if opts.PlacekeyColumn != "" {
+		row = append(row, pk)
+	}
+	if opts.H3OutColumn != "" {
+		h3, err := placekey.ToH3E(pk)
+		if err != nil {
+			return nil, err
+		}
+		row = append(row, h3)
+	}
+	if opts.WKTColumn != "" {
+		wkt, err := placekey.ToWKTE(pk)
+		if err != nil {
+			return nil, err
+		}
+		row = append(row, wkt)
+	}
+	return row, nil
+}
+
+func columnIndex(header []string, name string) int {
+	for i, h := range header {
+		if h == name {
+			return i
+		}
+	}
+	return -1
+}
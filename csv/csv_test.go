@@ -0,0 +1,53 @@
+package csv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEnrich(t *testing.T) {
+	in := "name,latitude,longitude\n" +
+		"twin peaks,37.7371,-122.44283\n" +
+		"nowhere,abc,-122.44283\n" +
+		"short,37.7371\n"
+
+	opts := DefaultOptions()
+	opts.H3OutColumn = "h3"
+
+	var out, rejects bytes.Buffer
+	stats, err := Enrich(strings.NewReader(in), &out, &rejects, opts)
+	if err != nil {
+		t.Fatalf(`Enrich error = %v; wanted nil`, err)
+	}
+	if stats.Rows != 3 || stats.Rejected != 2 {
+		t.Errorf(`Enrich stats = %+v; wanted {Rows:3 Rejected:2}`, stats)
+	}
+
+	want := "name,latitude,longitude,placekey,h3\n" +
+		"twin peaks,37.7371,-122.44283,@5vg-82n-kzz,8a2830953157fff\n"
+	if out.String() != want {
+		t.Errorf(`Enrich output = %q; wanted %q`, out.String(), want)
+	}
+	if lines := strings.Split(strings.TrimSpace(rejects.String()), "\n"); len(lines) != 3 || lines[0] != "name,latitude,longitude,error" {
+		t.Errorf(`Enrich rejects = %q; wanted a header and 2 rows`, rejects.String())
+	}
+}
+
+func TestEnrichH3Column(t *testing.T) {
+	in := "h3\n8a754e64992ffff\n"
+
+	opts := Options{H3Column: "h3", PlacekeyColumn: "pk"}
+
+	var out bytes.Buffer
+	if _, err := Enrich(strings.NewReader(in), &out, nil, opts); err != nil {
+		t.Fatalf(`Enrich error = %v; wanted nil`, err)
+	}
+	if want := "h3,pk\n8a754e64992ffff,@dvt-smp-tvz\n"; out.String() != want {
+		t.Errorf(`Enrich output = %q; wanted %q`, out.String(), want)
+	}
+
+	if _, err := Enrich(strings.NewReader(in), &out, nil, DefaultOptions()); err == nil {
+		t.Errorf(`Enrich without latitude column error = nil; wanted an error`)
+	}
+}
@@ -0,0 +1,213 @@
+package placekey
+
+import (
+	"math"
+	"strconv"
+)
+
+// DistanceMethod selects how DistanceWith measures the distance between two Placekeys.
+type DistanceMethod int
+
+const (
+	// Haversine is the great circle distance in meters between the centers of two Placekeys on
+	// a sphere, as returned by Distance.
+	Haversine DistanceMethod = iota
+	// Vincenty is the geodesic distance in meters between the centers of two Placekeys on the
+	// WGS84 ellipsoid.
+	Vincenty
+	// Boundary is the great circle distance in meters between the nearest points of the
+	// boundaries of two Placekeys, which is 0 for the same or adjacent Placekeys.
+	Boundary
+	// Grid is the number of steps between two Placekeys on the hexagon grid.
+	Grid
+)
+
+// DistanceOptions configures DistanceWith.
+type DistanceOptions struct {
+	Method DistanceMethod
+}
+
+// WGS84 ellipsoid
+const (
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+	wgs84B = (1 - wgs84F) * wgs84A
+)
+
+// DistanceWith returns the distance between two Placekeys measured with a DistanceMethod. It
+// returns NaN if either Placekey or the method is invalid, or if the Grid distance can't be
+// found.
+func DistanceWith(placekey1, placekey2 string, opts DistanceOptions) float64 {
+	d, err := DistanceWithE(placekey1, placekey2, opts)
+	if err != nil {
+		return math.NaN()
+	}
+	return d
+}
+
+// DistanceWithE returns the distance between two Placekeys measured with a DistanceMethod,
+// returning an error if either Placekey or the method is invalid, or if the Grid distance can't
+// be found because the Placekeys are too far apart or on opposite sides of a pentagon.
+func DistanceWithE(placekey1, placekey2 string, opts DistanceOptions) (float64, error) {
+	h3Int1, err := ParseWhere(placekey1)
+	if err != nil {
+		return 0, err
+	}
+	h3Int2, err := ParseWhere(placekey2)
+	if err != nil {
+		return 0, err
+	}
+
+	switch opts.Method {
+	case Haversine:
+		return geoDistance(h3Indexer.ToGeo(h3Int1), h3Indexer.ToGeo(h3Int2)), nil
+	case Vincenty:
+		return vincentyDistance(h3Indexer.ToGeo(h3Int1), h3Indexer.ToGeo(h3Int2)), nil
+	case Boundary:
+		return boundaryDistance(h3Int1, h3Int2), nil
+	case Grid:
+		d := h3Indexer.GridDistance(h3Int1, h3Int2)
+		if d < 0 {
+			return 0, &Error{Input: "(" + placekey1 + ", " + placekey2 + ")", Err: ErrInvalidLine}
+		}
+		return float64(d), nil
+	}
+	return 0, &Error{Input: strconv.Itoa(int(opts.Method)), Err: ErrInvalidDistance}
+}
+
+// Bearing returns the initial bearing in degrees clockwise from north, from 0 up to 360, of the
+// great circle from the center of one Placekey to the center of another. It returns NaN if
+// either Placekey is invalid.
+func Bearing(placekey1, placekey2 string) float64 {
+	b, err := BearingE(placekey1, placekey2)
+	if err != nil {
+		return math.NaN()
+	}
+	return b
+}
+
+// BearingE returns the initial bearing in degrees clockwise from north, from 0 up to 360, of
+// the great circle from the center of one Placekey to the center of another, returning an
+// error if either Placekey is invalid.
+func BearingE(placekey1, placekey2 string) (float64, error) {
+	h3Int1, err := ParseWhere(placekey1)
+	if err != nil {
+		return 0, err
+	}
+	h3Int2, err := ParseWhere(placekey2)
+	if err != nil {
+		return 0, err
+	}
+	return geoBearing(h3Indexer.ToGeo(h3Int1), h3Indexer.ToGeo(h3Int2)), nil
+}
+
+// Bearing returns the initial bearing in degrees clockwise from north, from 0 up to 360, of the
+// great circle from the center of the Placekey to the center of another, or NaN if either is
+// the zero value.
+func (pk Placekey) Bearing(other Placekey) float64 {
+	if pk.IsZero() || other.IsZero() {
+		return math.NaN()
+	}
+	return geoBearing(h3Indexer.ToGeo(pk.h3), h3Indexer.ToGeo(other.h3))
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+func geoBearing(geo1, geo2 LatLng) float64 {
+	lat1, lat2 := rad(geo1.Lat), rad(geo2.Lat)
+	dLon := rad(geo2.Lng - geo1.Lng)
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Mod(deg(math.Atan2(y, x))+360, 360)
+}
+
+// vincentyDistance returns the geodesic distance in meters between two points on the WGS84
+// ellipsoid with Vincenty's inverse formula. The formula doesn't converge for nearly antipodal
+// points, which fall back to the haversine distance.
+func vincentyDistance(geo1, geo2 LatLng) float64 {
+	L := rad(geo2.Lng - geo1.Lng)
+	U1 := math.Atan((1 - wgs84F) * math.Tan(rad(geo1.Lat)))
+	U2 := math.Atan((1 - wgs84F) * math.Tan(rad(geo2.Lat)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Sqrt((cosU2*sinLambda)*(cosU2*sinLambda) +
+			(cosU1*sinU2-sinU1*cosU2*cosLambda)*(cosU1*sinU2-sinU1*cosU2*cosLambda))
+		if sinSigma == 0 {
+			// coincident points
+			return 0
+		}
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha := 1 - sinAlpha*sinAlpha
+		cos2SigmaM := 0.0
+		if cosSqAlpha != 0 {
+			// not on the equator
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		C := wgs84F / 16 * cosSqAlpha * (4 + wgs84F*(4-3*cosSqAlpha))
+		lambdaPrev := lambda
+		lambda = L + (1-C)*wgs84F*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-lambdaPrev) < 1e-12 {
+			uSq := cosSqAlpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+			A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+			B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+			deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+				B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+			return wgs84B * A * (sigma - deltaSigma)
+		}
+	}
+	return geoDistance(geo1, geo2)
+}
+
+// boundaryDistance returns the great circle distance in meters between the nearest points of
+// the boundaries of two cells, or 0 if they are the same or adjacent. The nearest points of two
+// convex polygons that don't touch include a vertex of one of them.
+func boundaryDistance(h3Int1, h3Int2 uint64) float64 {
+	for _, h := range h3Indexer.KRing(h3Int1, 1) {
+		if h == h3Int2 {
+			return 0
+		}
+	}
+	boundary1 := h3Indexer.ToGeoBoundary(h3Int1)
+	boundary2 := h3Indexer.ToGeoBoundary(h3Int2)
+	return math.Min(verticesToEdgesDistance(boundary1, boundary2), verticesToEdgesDistance(boundary2, boundary1))
+}
+
+// verticesToEdgesDistance returns the shortest distance in meters from a vertex of one boundary
+// to an edge of another.
+func verticesToEdgesDistance(vertices, edges []LatLng) float64 {
+	d := math.Inf(1)
+	for _, p := range vertices {
+		for i := range edges {
+			d = math.Min(d, pointSegmentDistance(p, edges[i], edges[(i+1)%len(edges)]))
+		}
+	}
+	return d
+}
+
+// pointSegmentDistance returns the great circle distance in meters from p to the nearest point
+// of the great circle arc from a to b.
+func pointSegmentDistance(p, a, b LatLng) float64 {
+	earthRadius := 6371.0 * 1000 // m, as in geoDistance
+
+	d13 := geoDistance(a, p) / earthRadius
+	d12 := geoDistance(a, b) / earthRadius
+	theta := rad(geoBearing(a, p) - geoBearing(a, b))
+	if math.Cos(theta) <= 0 || d12 == 0 {
+		// p is behind a
+		return geoDistance(a, p)
+	}
+	crossTrack := math.Asin(math.Sin(d13) * math.Sin(theta))
+	alongTrack := math.Acos(math.Max(-1, math.Min(1, math.Cos(d13)/math.Cos(crossTrack))))
+	if alongTrack >= d12 {
+		// p is beyond b
+		return geoDistance(b, p)
+	}
+	return math.Abs(crossTrack) * earthRadius
+}
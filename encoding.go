@@ -0,0 +1,76 @@
+package placekey
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// IsZero returns whether the Placekey is the zero value.
+func (pk Placekey) IsZero() bool {
+	return pk == Placekey{}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The zero Placekey has no text
+// that UnmarshalText accepts, so it returns an error; MarshalJSON and Value encode it as null.
+func (pk Placekey) MarshalText() ([]byte, error) {
+	if pk.IsZero() {
+		return nil, &Error{Input: "", Err: ErrInvalidWhere}
+	}
+	return []byte(pk.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. It applies the same rules
+// as Parse, so an empty string is an invalid where part.
+func (pk *Placekey) UnmarshalText(text []byte) error {
+	p, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*pk = p
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. The zero Placekey marshals to null.
+func (pk Placekey) MarshalJSON() ([]byte, error) {
+	if pk.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(pk.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. A null value leaves the Placekey
+// unchanged.
+func (pk *Placekey) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return pk.UnmarshalText([]byte(s))
+}
+
+// Scan implements the sql.Scanner interface. A NULL value scans to the zero Placekey.
+func (pk *Placekey) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*pk = Placekey{}
+		return nil
+	case string:
+		return pk.UnmarshalText([]byte(src))
+	case []byte:
+		return pk.UnmarshalText(src)
+	default:
+		return fmt.Errorf("placekey: cannot scan type %T into Placekey", src)
+	}
+}
+
+// Value implements the driver.Valuer interface. The zero Placekey is stored as NULL.
+func (pk Placekey) Value() (driver.Value, error) {
+	if pk.IsZero() {
+		return nil, nil
+	}
+	return pk.String(), nil
+}
@@ -0,0 +1,287 @@
+package placekey
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkt"
+	"github.com/paulmach/orb/geojson"
+)
+
+var (
+	// ErrInvalidWhat is returned when the what part of a Placekey is malformed.
+	ErrInvalidWhat = errors.New("invalid what part")
+	// ErrInvalidWhere is returned when the where part of a Placekey is malformed.
+	ErrInvalidWhere = errors.New("invalid where part")
+	// ErrInvalidCharacter is returned when a Placekey contains a character outside of its alphabet.
+	ErrInvalidCharacter = errors.New("invalid character")
+	// ErrInvalidH3 is returned when a value does not decode to a valid H3 cell.
+	ErrInvalidH3 = errors.New("invalid H3 cell")
+	// ErrInvalidResolution is returned when an H3 cell is not at the Placekey resolution.
+	ErrInvalidResolution = errors.New("invalid H3 resolution")
+	// ErrInvalidCoordinate is returned when a latitude or longitude is out of range.
+	ErrInvalidCoordinate = errors.New("invalid coordinate")
+	// ErrInvalidK is returned when a number of steps between hexagons is negative.
+	ErrInvalidK = errors.New("invalid k")
+	// ErrInvalidRadius is returned when a radius is negative, NaN or too large.
+	ErrInvalidRadius = errors.New("invalid radius")
+	// ErrInvalidDistance is returned when a distance is negative, NaN or too large.
+	ErrInvalidDistance = errors.New("invalid distance")
+	// ErrInvalidLine is returned when no line of hexagons can be found between two Placekeys,
+	// because they are too far apart or on opposite sides of a pentagon.
+	ErrInvalidLine = errors.New("invalid line")
+	// ErrTooManyCells is returned when cells would expand into more than MaxChildren Placekeys.
+	ErrTooManyCells = errors.New("too many cells")
+)
+
+// Error records a failed conversion and the input that caused it. Err is one of the
+// ErrInvalid errors and can be checked with errors.Is.
+type Error struct {
+	Input string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return "placekey: " + strconv.Quote(e.Input) + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ParseWhere validates a Placekey and returns the H3 integer of its where part.
+func ParseWhere(placekey string) (uint64, error) {
+	what, where := parsePlacekey(placekey)
+	if strings.Count(placekey, "@") > 1 {
+		return 0, &Error{Input: placekey, Err: ErrInvalidWhere}
+	}
+	if what != "" {
+		if strings.Trim(what, alphabet+"-") != "" {
+			return 0, &Error{Input: placekey, Err: ErrInvalidCharacter}
+		}
+		if !whatRegex.MatchString(what) {
+			return 0, &Error{Input: placekey, Err: ErrInvalidWhat}
+		}
+	}
+	if strings.Trim(where, alphabet+replacementChars+paddingChar+"-") != "" {
+		return 0, &Error{Input: placekey, Err: ErrInvalidCharacter}
+	}
+	if !whereRegex.MatchString(where) {
+		return 0, &Error{Input: placekey, Err: ErrInvalidWhere}
+	}
+	h3Int := decodeToH3Int(where)
+	if err := checkH3Int(h3Int); err != nil {
+		return 0, &Error{Input: placekey, Err: err}
+	}
+	return h3Int, nil
+}
+
+// FromGeoE converts a (latitude, longitude) into a Placekey, returning an error if the
+// coordinate is out of range.
+func FromGeoE(lat, lon float64) (string, error) {
+	if math.IsNaN(lat) || math.IsNaN(lon) || math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+		return "", &Error{Input: fmt.Sprintf("(%f, %f)", lat, lon), Err: ErrInvalidCoordinate}
+	}
+	return FromGeo(lat, lon), nil
+}
+
+// FromCircleE returns the interior and boundary Placekeys of a circle of a radius in meters
+// around a (latitude, longitude), returning an error if the coordinate is out of range or the
+// radius is negative, NaN or over MaxRadius.
+func FromCircleE(lat, lon, radius float64) ([]string, []string, error) {
+	if _, err := FromGeoE(lat, lon); err != nil {
+		return nil, nil, err
+	}
+	if math.IsNaN(radius) || radius < 0 || radius > MaxRadius {
+		return nil, nil, &Error{Input: strconv.FormatFloat(radius, 'f', -1, 64), Err: ErrInvalidRadius}
+	}
+	interior, boundary := FromCircle(lat, lon, radius)
+	return interior, boundary, nil
+}
+
+// FromLineStringE returns the Placekeys of the hexagons that a LineString passes through or
+// comes within a buffer in meters of, returning an error if a point is out of range or the
+// buffer is negative, NaN or over MaxRadius.
+func FromLineStringE(ls orb.LineString, buffer float64) ([]string, error) {
+	for _, p := range ls {
+		if _, err := FromGeoE(p[1], p[0]); err != nil {
+			return nil, err
+		}
+	}
+	if math.IsNaN(buffer) || buffer < 0 || buffer > MaxRadius {
+		return nil, &Error{Input: strconv.FormatFloat(buffer, 'f', -1, 64), Err: ErrInvalidDistance}
+	}
+	return FromLineString(ls, buffer), nil
+}
+
+// FromMultiPointE returns the Placekeys of the hexagons that contain the points of a
+// MultiPoint, returning an error if a point is out of range.
+func FromMultiPointE(mp orb.MultiPoint) ([]string, error) {
+	for _, p := range mp {
+		if _, err := FromGeoE(p[1], p[0]); err != nil {
+			return nil, err
+		}
+	}
+	return FromMultiPoint(mp), nil
+}
+
+// ToGeoE converts a Placekey into a (latitude, longitude), returning an error if the
+// Placekey is invalid.
+func ToGeoE(placekey string) (float64, float64, error) {
+	h3Int, err := ParseWhere(placekey)
+	if err != nil {
+		return 0, 0, err
+	}
+	geo := h3Indexer.ToGeo(h3Int)
+	return geo.Lat, geo.Lng, nil
+}
+
+// ToH3E converts a Placekey string into an H3 string, returning an error if the
+// Placekey is invalid.
+func ToH3E(placekey string) (string, error) {
+	h3Int, err := ParseWhere(placekey)
+	if err != nil {
+		return "", err
+	}
+	return h3Indexer.ToString(h3Int), nil
+}
+
+// FromH3E converts an H3 hexadecimal string into a Placekey string, returning an error if
+// the string is not a valid H3 cell at the Placekey resolution.
+func FromH3E(h3String string) (string, error) {
+	h3Int, err := strconv.ParseUint(h3String, 16, 64)
+	if err != nil {
+		return "", &Error{Input: h3String, Err: ErrInvalidH3}
+	}
+	if err := checkH3Int(h3Int); err != nil {
+		return "", &Error{Input: h3String, Err: err}
+	}
+	return encodeH3Int(h3Int), nil
+}
+
+// FromH3IntE converts an H3 integer into a Placekey, returning an error if the integer is
+// not a valid H3 cell at the Placekey resolution.
+func FromH3IntE(h3Int uint64) (string, error) {
+	if err := checkH3Int(h3Int); err != nil {
+		return "", &Error{Input: strconv.FormatUint(h3Int, 10), Err: err}
+	}
+	return encodeH3Int(h3Int), nil
+}
+
+// ToH3IntE converts a Placekey to an H3 integer, returning an error if the Placekey is invalid.
+func ToH3IntE(placekey string) (uint64, error) {
+	return ParseWhere(placekey)
+}
+
+// ToHexBoundaryE returns the Polygon boundary of a Placekey as a slice of (latitude, longitude)
+// coordinates, returning an error if the Placekey is invalid.
+func ToHexBoundaryE(placekey string) ([][]float64, error) {
+	if _, err := ParseWhere(placekey); err != nil {
+		return nil, err
+	}
+	return ToHexBoundary(placekey), nil
+}
+
+// ToPolygonE returns the Polygon boundary of a Placekey as an orb.Polygon, returning an error
+// if the Placekey is invalid.
+func ToPolygonE(placekey string) (orb.Polygon, error) {
+	h3Int, err := ParseWhere(placekey)
+	if err != nil {
+		return nil, err
+	}
+	return latLngsToOrbPolygon(h3Indexer.ToGeoBoundary(h3Int)), nil
+}
+
+// ToGeoJSONE returns the Polygon boundary of a Placekey as a GeoJSON Feature string,
+// returning an error if the Placekey is invalid.
+func ToGeoJSONE(placekey string) (string, error) {
+	p, err := ToPolygonE(placekey)
+	if err != nil {
+		return "", err
+	}
+	b, err := geojson.NewFeature(p).MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ToWKTE returns the Polygon boundary of a Placekey as a Well-Known Text (WKT) string,
+// returning an error if the Placekey is invalid.
+func ToWKTE(placekey string) (string, error) {
+	p, err := ToPolygonE(placekey)
+	if err != nil {
+		return "", err
+	}
+	return wkt.MarshalString(p), nil
+}
+
+// ToMultiPolygonE dissolves the hexagons of a set of Placekeys into the outline of the area
+// they cover, returning an error if a Placekey is invalid.
+func ToMultiPolygonE(placekeys []string) (orb.MultiPolygon, error) {
+	for _, pk := range placekeys {
+		if _, err := ParseWhere(pk); err != nil {
+			return nil, err
+		}
+	}
+	return ToMultiPolygon(placekeys), nil
+}
+
+// ToGeoJSONFeatureCollectionE returns the dissolved outline of a set of Placekeys as a GeoJSON
+// FeatureCollection string, returning an error if a Placekey is invalid.
+func ToGeoJSONFeatureCollectionE(placekeys []string) (string, error) {
+	mp, err := ToMultiPolygonE(placekeys)
+	if err != nil {
+		return "", err
+	}
+	fc := geojson.NewFeatureCollection()
+	for _, p := range mp {
+		fc.Append(geojson.NewFeature(p))
+	}
+	b, err := fc.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ToMultiPolygonWKTE returns the dissolved outline of a set of Placekeys as a Well-Known Text
+// (WKT) MultiPolygon string, returning an error if a Placekey is invalid.
+func ToMultiPolygonWKTE(placekeys []string) (string, error) {
+	mp, err := ToMultiPolygonE(placekeys)
+	if err != nil {
+		return "", err
+	}
+	return wkt.MarshalString(mp), nil
+}
+
+// DistanceE returns the distance in meters between the centers of two Placekeys, returning
+// an error if either Placekey is invalid.
+func DistanceE(placekey1, placekey2 string) (float64, error) {
+	h3Int1, err := ParseWhere(placekey1)
+	if err != nil {
+		return 0, err
+	}
+	h3Int2, err := ParseWhere(placekey2)
+	if err != nil {
+		return 0, err
+	}
+	return geoDistance(h3Indexer.ToGeo(h3Int1), h3Indexer.ToGeo(h3Int2)), nil
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+func checkH3Int(h3Int uint64) error {
+	if !h3Indexer.IsValid(h3Int) {
+		return ErrInvalidH3
+	}
+	if h3Indexer.Resolution(h3Int) != resolution {
+		return ErrInvalidResolution
+	}
+	return nil
+}
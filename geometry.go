@@ -0,0 +1,491 @@
+package placekey
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// sampleSpacing is the maximum distance in meters between points sampled along an edge,
+// chosen to be well under the inradius of a resolution 10 hexagon.
+const sampleSpacing float64 = 20.0
+
+// epsilon is the tolerance used by the planar predicates so that hexagon edges shared
+// between neighbors are treated as touching rather than crossing.
+const epsilon float64 = 1e-9
+
+type pointLocation int
+
+const (
+	outside pointLocation = iota
+	onBoundary
+	inside
+)
+
+// polygonCandidateHexes returns every hex that could intersect a Polygon: the polyfill of
+// the Polygon, the hexes along each of its rings and the neighbors of those hexes. It also
+// returns which of them are along the rings. The rings are sampled more densely than the size
+// of a hex, so no other hex can cross them, and the rest of the polyfill is inside the Polygon.
+func polygonCandidateHexes(p orb.Polygon) ([]uint64, map[uint64]bool) {
+	seen := map[uint64]bool{}
+	candidates := []uint64{}
+	add := func(h uint64) {
+		if !seen[h] {
+			seen[h] = true
+			candidates = append(candidates, h)
+		}
+	}
+
+	geofence, holes := orbPolygonToLatLngs(p)
+	for _, h := range h3Indexer.Polyfill(geofence, holes, resolution) {
+		add(h)
+	}
+	onRings := map[uint64]bool{}
+	for _, r := range p {
+		for _, c := range densifyPoints(r) {
+			for _, h := range h3Indexer.KRing(h3Indexer.FromGeo(c[1], c[0], resolution), 1) {
+				add(h)
+				onRings[h] = true
+			}
+		}
+	}
+	return candidates, onRings
+}
+
+// circleK returns the size of a k-ring around a hex that covers every hex intersecting a circle
+// of a radius in meters around a point in the hex. It is based on the shortest edge of the hex,
+// with a ring to spare for the distortion of hexes further away.
+func circleK(h uint64, radius float64) int {
+	boundary := h3Indexer.ToGeoBoundary(h)
+	edge := math.Inf(1)
+	for i := range boundary {
+		edge = math.Min(edge, geoDistance(boundary[i], boundary[(i+1)%len(boundary)]))
+	}
+	// the centers of the hexes in ring k are at least 1.5 edges apart per ring, and a hex
+	// intersects the circle if its center is within an edge of it
+	return int(math.Ceil((radius+2*edge)/(1.5*edge))) + 1
+}
+
+// clampRadius returns a radius or buffer in meters in the range FromCircle and FromLineString
+// cover, treating a negative or NaN radius as 0.
+func clampRadius(radius float64) float64 {
+	if !(radius > 0) {
+		return 0
+	}
+	return math.Min(radius, MaxRadius)
+}
+
+// circleHexRelation returns whether a hex with a boundary is inside a circle of a radius in
+// meters, and whether any of its edges comes within the radius of the center. The hex that
+// contains the center is left to the caller.
+func circleHexRelation(center LatLng, radius float64, boundary []LatLng) (bool, bool) {
+	contains, intersects := true, false
+	for i := range boundary {
+		if geoDistance(center, boundary[i]) > radius {
+			contains = false
+		}
+		closest := closestPointOnSegment(center, boundary[i], boundary[(i+1)%len(boundary)])
+		if geoDistance(center, closest) <= radius {
+			intersects = true
+		}
+	}
+	return contains, intersects
+}
+
+// segmentNearHex returns whether segment ab passes through the interior of a hex with a
+// boundary, or comes within a buffer in meters of it when the buffer is positive. The segment
+// is assumed to be much shorter than the hex, as it is between points from densifyPoints.
+// Longitudes are shifted next to b's, so that segments and hexes on the antimeridian compare in
+// the same range.
+func segmentNearHex(a, b orb.Point, buffer float64, boundary []LatLng) bool {
+	a[0] = b[0] + wrapLng(a[0]-b[0])
+	shifted := make([]LatLng, len(boundary))
+	for i, v := range boundary {
+		shifted[i] = LatLng{Lat: v.Lat, Lng: b[0] + wrapLng(v.Lng-b[0])}
+	}
+	boundary = shifted
+	hexPoly := latLngsToOrbPolygon(boundary)
+	for _, pt := range []orb.Point{a, b, midpoint(a, b)} {
+		if polygonPointLocation(hexPoly, pt) == inside {
+			return true
+		}
+	}
+	for i := 1; i < len(hexPoly[0]); i++ {
+		if segmentsCross(a, b, hexPoly[0][i-1], hexPoly[0][i]) {
+			return true
+		}
+	}
+	if buffer <= 0 {
+		return false
+	}
+
+	// segments that don't cross are nearest at an endpoint of one of them
+	la, lb := LatLng{Lat: a[1], Lng: a[0]}, LatLng{Lat: b[1], Lng: b[0]}
+	for i := range boundary {
+		if pointSegmentDistance(boundary[i], la, lb) <= buffer ||
+			pointSegmentDistance(la, boundary[i], boundary[(i+1)%len(boundary)]) <= buffer ||
+			pointSegmentDistance(lb, boundary[i], boundary[(i+1)%len(boundary)]) <= buffer {
+			return true
+		}
+	}
+	return false
+}
+
+// closestPointOnSegment returns the point of segment ab closest to p, using a local
+// equirectangular projection around p.
+func closestPointOnSegment(p, a, b LatLng) LatLng {
+	scale := math.Cos(rad(p.Lat))
+	ax, ay := wrapLng(a.Lng-p.Lng)*scale, a.Lat-p.Lat
+	dx, dy := wrapLng(b.Lng-a.Lng)*scale, b.Lat-a.Lat
+	t := 0.0
+	if d := dx*dx + dy*dy; d > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/d))
+	}
+	return LatLng{Lat: a.Lat + t*(b.Lat-a.Lat), Lng: a.Lng + t*wrapLng(b.Lng-a.Lng)}
+}
+
+// wrapLng wraps a difference of longitudes into [-180, 180].
+func wrapLng(d float64) float64 {
+	if d > 180 {
+		return d - 360
+	}
+	if d < -180 {
+		return d + 360
+	}
+	return d
+}
+
+// densifyPoints returns the vertices of a path along with points interpolated between them,
+// spaced no more than sampleSpacing meters apart. Points are interpolated the short way around
+// the globe, across the antimeridian if that is shorter.
+func densifyPoints(path []orb.Point) []orb.Point {
+	if len(path) == 0 {
+		return nil
+	}
+	points := []orb.Point{path[0]}
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		d := geoDistance(
+			LatLng{Lat: a[1], Lng: a[0]},
+			LatLng{Lat: b[1], Lng: b[0]},
+		)
+		n := int(math.Ceil(d / sampleSpacing))
+		for j := 1; j < n; j++ {
+			t := float64(j) / float64(n)
+			points = append(points, orb.Point{wrapLng(a[0] + t*wrapLng(b[0]-a[0])), a[1] + t*(b[1]-a[1])})
+		}
+		points = append(points, b)
+	}
+	return points
+}
+
+// polygonIndex buckets the edges of a Polygon into horizontal bands, so that locating a point
+// or finding the edges near a hex only looks at the edges that span the same latitudes instead
+// of every edge of the Polygon.
+type polygonIndex struct {
+	minY     float64
+	bandSize float64
+	bands    [][][2]orb.Point
+}
+
+func newPolygonIndex(p orb.Polygon) *polygonIndex {
+	bound := p.Bound()
+	edges := 0
+	for _, r := range p {
+		edges += len(r)
+	}
+	n := edges
+	if n > 1<<16 {
+		n = 1 << 16
+	}
+	if n < 1 {
+		n = 1
+	}
+	idx := &polygonIndex{minY: bound.Min[1], bandSize: (bound.Max[1] - bound.Min[1]) / float64(n)}
+	if idx.bandSize <= 0 {
+		idx.bandSize = 1
+	}
+	idx.bands = make([][][2]orb.Point, n)
+	for _, r := range p {
+		for i := range r {
+			a, b := r[i], r[(i+1)%len(r)]
+			lo, hi := idx.band(math.Min(a[1], b[1])-epsilon), idx.band(math.Max(a[1], b[1])+epsilon)
+			for j := lo; j <= hi; j++ {
+				idx.bands[j] = append(idx.bands[j], [2]orb.Point{a, b})
+			}
+		}
+	}
+	return idx
+}
+
+func (idx *polygonIndex) band(y float64) int {
+	i := int(math.Floor((y - idx.minY) / idx.bandSize))
+	if i < 0 {
+		return 0
+	}
+	if i >= len(idx.bands) {
+		return len(idx.bands) - 1
+	}
+	return i
+}
+
+// locate returns where a point is relative to the Polygon. Holes are handled by counting the
+// crossings of every ring together, which is the same as for ringPointLocation for Polygons
+// whose holes are inside their exterior ring.
+func (idx *polygonIndex) locate(pt orb.Point) pointLocation {
+	in := false
+	for _, e := range idx.bands[idx.band(pt[1])] {
+		a, b := e[0], e[1]
+		if pointOnSegment(pt, a, b) {
+			return onBoundary
+		}
+		if (a[1] > pt[1]) != (b[1] > pt[1]) &&
+			pt[0] < (b[0]-a[0])*(pt[1]-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+	if in {
+		return inside
+	}
+	return outside
+}
+
+// near calls f with each edge of the Polygon whose bounding box overlaps a bound, until f
+// returns true. Edges spanning several bands can be passed more than once.
+func (idx *polygonIndex) near(bound orb.Bound, f func(a, b orb.Point) bool) bool {
+	for i := idx.band(bound.Min[1] - epsilon); i <= idx.band(bound.Max[1]+epsilon); i++ {
+		for _, e := range idx.bands[i] {
+			if math.Max(e[0][0], e[1][0]) < bound.Min[0]-epsilon || math.Min(e[0][0], e[1][0]) > bound.Max[0]+epsilon ||
+				math.Max(e[0][1], e[1][1]) < bound.Min[1]-epsilon || math.Min(e[0][1], e[1][1]) > bound.Max[1]+epsilon {
+				continue
+			}
+			if f(e[0], e[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsPolygon returns whether q lies entirely within the Polygon, boundaries included.
+func (idx *polygonIndex) containsPolygon(q orb.Polygon) bool {
+	for _, r := range q {
+		for i, c := range r {
+			if idx.locate(c) == outside {
+				return false
+			}
+			if i > 0 && idx.locate(midpoint(r[i-1], c)) == outside {
+				return false
+			}
+		}
+	}
+	return !idx.near(q.Bound(), func(a, b orb.Point) bool {
+		return polygonPointLocation(q, a) == inside || segmentCrossesPolygon(a, b, q)
+	})
+}
+
+// overlapsPolygon returns whether the interiors of q and the Polygon intersect, which excludes
+// polygons that only touch along their boundaries.
+func (idx *polygonIndex) overlapsPolygon(q orb.Polygon) bool {
+	for _, r := range q {
+		for i, c := range r {
+			if idx.locate(c) == inside {
+				return true
+			}
+			if i > 0 && idx.locate(midpoint(r[i-1], c)) == inside {
+				return true
+			}
+		}
+	}
+	return idx.near(q.Bound(), func(a, b orb.Point) bool {
+		return polygonPointLocation(q, a) == inside || segmentCrossesPolygon(a, b, q)
+	})
+}
+
+func segmentCrossesPolygon(a, b orb.Point, q orb.Polygon) bool {
+	for _, r := range q {
+		for j := 1; j < len(r); j++ {
+			if segmentsCross(a, b, r[j-1], r[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func polygonPointLocation(p orb.Polygon, pt orb.Point) pointLocation {
+	if len(p) == 0 {
+		return outside
+	}
+	loc := ringPointLocation(p[0], pt)
+	if loc != inside {
+		return loc
+	}
+	for _, hole := range p[1:] {
+		switch ringPointLocation(hole, pt) {
+		case inside:
+			return outside
+		case onBoundary:
+			return onBoundary
+		}
+	}
+	return inside
+}
+
+func ringPointLocation(r orb.Ring, pt orb.Point) pointLocation {
+	in := false
+	for i := 0; i < len(r); i++ {
+		a, b := r[i], r[(i+1)%len(r)]
+		if pointOnSegment(pt, a, b) {
+			return onBoundary
+		}
+		if (a[1] > pt[1]) != (b[1] > pt[1]) &&
+			pt[0] < (b[0]-a[0])*(pt[1]-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+	if in {
+		return inside
+	}
+	return outside
+}
+
+func pointOnSegment(pt, a, b orb.Point) bool {
+	if orientation(a, b, pt) != 0 {
+		return false
+	}
+	return math.Min(a[0], b[0])-epsilon <= pt[0] && pt[0] <= math.Max(a[0], b[0])+epsilon &&
+		math.Min(a[1], b[1])-epsilon <= pt[1] && pt[1] <= math.Max(a[1], b[1])+epsilon
+}
+
+// segmentsCross returns whether segments ab and cd properly cross, not counting segments
+// that only touch at an endpoint or overlap along a shared line.
+func segmentsCross(a, b, c, d orb.Point) bool {
+	o1 := orientation(a, b, c)
+	o2 := orientation(a, b, d)
+	o3 := orientation(c, d, a)
+	o4 := orientation(c, d, b)
+	return o1*o2 < 0 && o3*o4 < 0
+}
+
+// orientation returns 1 if abc turns counterclockwise, -1 if clockwise and 0 if collinear.
+func orientation(a, b, c orb.Point) int {
+	v := (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+	scale := math.Max(math.Abs(b[0]-a[0])+math.Abs(b[1]-a[1]), math.Abs(c[0]-a[0])+math.Abs(c[1]-a[1]))
+	if math.Abs(v) <= epsilon*scale*scale+epsilon*epsilon {
+		return 0
+	}
+	if v > 0 {
+		return 1
+	}
+	return -1
+}
+
+func midpoint(a, b orb.Point) orb.Point {
+	return orb.Point{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+}
+
+// vertexSnap is the size in degrees of the grid that hex vertices are snapped to when matching
+// the vertices that neighboring hexes share, which can differ in the last few bits.
+const vertexSnap float64 = 1e-8
+
+// dissolveHexes returns the outline of a set of distinct hexes. Every hex ring runs
+// counterclockwise, so an edge shared by two hexes appears once in each direction and cancels
+// out. The edges left over chain into counterclockwise outer rings and clockwise holes. Three
+// hexes meet at each vertex, so no vertex starts more than one of the edges left over.
+// Longitudes are shifted into a continuous range around the first hex, so that hexes on either
+// side of the antimeridian join up, and the outline may run past 180 or -180.
+func dissolveHexes(h3Ints []uint64) orb.MultiPolygon {
+	ref := 0.0
+	if len(h3Ints) > 0 {
+		ref = h3Indexer.ToGeo(h3Ints[0]).Lng
+	}
+
+	vertices := []orb.Point{}
+	ids := map[[2]int64]int{}
+	vertexID := func(p orb.Point) int {
+		x, y := int64(math.Round(p[0]/vertexSnap)), int64(math.Round(p[1]/vertexSnap))
+		for dx := int64(-1); dx <= 1; dx++ {
+			for dy := int64(-1); dy <= 1; dy++ {
+				if id, ok := ids[[2]int64{x + dx, y + dy}]; ok {
+					return id
+				}
+			}
+		}
+		ids[[2]int64{x, y}] = len(vertices)
+		vertices = append(vertices, p)
+		return len(vertices) - 1
+	}
+
+	type edge struct{ from, to int }
+	edges := []edge{}
+	index := map[edge]int{}
+	removed := map[int]bool{}
+	for _, h := range h3Ints {
+		boundary := h3Indexer.ToGeoBoundary(h)
+		for i := range boundary {
+			boundary[i].Lng = ref + wrapLng(boundary[i].Lng-ref)
+		}
+		ring := latLngsToOrbPolygon(boundary)[0]
+		for i := 1; i < len(ring); i++ {
+			e := edge{vertexID(ring[i-1]), vertexID(ring[i])}
+			if j, ok := index[edge{e.to, e.from}]; ok && !removed[j] {
+				removed[j] = true
+				continue
+			}
+			index[e] = len(edges)
+			edges = append(edges, e)
+		}
+	}
+
+	next := map[int]int{}
+	for i, e := range edges {
+		if !removed[i] {
+			next[e.from] = i
+		}
+	}
+	outers, holes := []orb.Ring{}, []orb.Ring{}
+	for i, e := range edges {
+		if removed[i] {
+			continue
+		}
+		ring := orb.Ring{vertices[e.from]}
+		for j := i; !removed[j]; j = next[edges[j].to] {
+			removed[j] = true
+			ring = append(ring, vertices[edges[j].to])
+		}
+		if ring.Orientation() == orb.CW {
+			holes = append(holes, ring)
+		} else {
+			outers = append(outers, ring)
+		}
+	}
+
+	mp := make(orb.MultiPolygon, len(outers))
+	for i, r := range outers {
+		mp[i] = orb.Polygon{r}
+	}
+	// a hole belongs to the smallest outer ring around it, as outer rings can lie in the holes
+	// of others
+	for _, hole := range holes {
+		best := -1
+		for i, r := range outers {
+			if ringPointLocation(r, hole[0]) == inside && (best < 0 || ringArea(r) < ringArea(outers[best])) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			mp[best] = append(mp[best], hole)
+		}
+	}
+	return mp
+}
+
+// ringArea returns the planar area of a ring in square degrees.
+func ringArea(r orb.Ring) float64 {
+	a := 0.0
+	for i := 1; i < len(r); i++ {
+		a += r[i-1][0]*r[i][1] - r[i][0]*r[i-1][1]
+	}
+	return math.Abs(a) / 2
+}
@@ -3,8 +3,6 @@ module github.com/engelsjk/placekey-go
 go 1.15
 
 require (
-	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/paulmach/orb v0.1.7
-	github.com/stretchr/testify v1.6.1 // indirect
-	github.com/uber/h3-go v3.0.1+incompatible
+	github.com/paulmach/orb v0.7.1
+	github.com/uber/h3-go/v4 v4.1.0
 )
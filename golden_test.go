@@ -0,0 +1,339 @@
+package placekey
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the inputs of testdata/golden.json")
+
+const goldenPath = "testdata/golden.json"
+
+// goldenFile is a test corpus of H3 edge cases. Entry inputs are either a (latitude, longitude)
+// or an H3 cell; every other field is an expected output. writeGolden only writes inputs, and
+// testdata/generate.py computes the outputs with placekey-py. Source records what produced the
+// outputs: until generate.py has been run, the placekey fields come from the transcription of
+// placekey-py in conformance_test.go, so the corpus only checks this package against H3 v3 and
+// itself. placekeyPyExamples are the outputs placekey-py is known to produce.
+type goldenFile struct {
+	Source    string           `json:"source"`
+	Entries   []goldenEntry    `json:"entries"`
+	Distances []goldenDistance `json:"distances"`
+}
+
+type goldenEntry struct {
+	Name     string      `json:"name"`
+	Lat      *float64    `json:"lat,omitempty"`
+	Lon      *float64    `json:"lon,omitempty"`
+	H3       string      `json:"h3,omitempty"`
+	Placekey string      `json:"placekey,omitempty"`
+	Center   []float64   `json:"center,omitempty"`
+	Boundary [][]float64 `json:"boundary,omitempty"`
+}
+
+// goldenBoundaryExceptions are the known differences from the golden file, by entry name and
+// boundary vertex index. H3 v3, which placekey-py is built on, puts these vertices 45 degrees of
+// longitude away from the rest of their cell; H3 v4 returns them next to the other vertices.
+// Only their longitudes differ.
+var goldenBoundaryExceptions = map[string][]int{
+	"base cell 2":   {2, 5},
+	"base cell 119": {0, 3},
+}
+
+type goldenDistance struct {
+	Placekey1 string  `json:"placekey1"`
+	Placekey2 string  `json:"placekey2"`
+	Distance  float64 `json:"distance"`
+}
+
+// placekeyPyExamples are the outputs placekey-py prints in its README, for the point (0, 0).
+var placekeyPyExamples = struct {
+	lat, lon       float64
+	placekey, h3   string
+	center         [2]float64
+	other          string
+	distance       float64
+	valid, invalid string
+}{
+	lat: 0, lon: 0,
+	placekey: "@dvt-smp-tvz",
+	h3:       "8a754e64992ffff",
+	center:   [2]float64{0.00018033323813810344, -0.00018985758738881587},
+	other:    "@5vg-7gq-tjv",
+	distance: 12795124.895573696,
+	valid:    "222-227@dvt-smp-tvz",
+	invalid:  "@123-456-789",
+}
+
+func TestPlacekeyPyExamples(t *testing.T) {
+	ex := placekeyPyExamples
+	if got := FromGeo(ex.lat, ex.lon); got != ex.placekey {
+		t.Errorf(`FromGeo(%v, %v) = "%s"; placekey-py returns "%s"`, ex.lat, ex.lon, got, ex.placekey)
+	}
+	if lat, lon := ToGeo(ex.placekey); !closeTo(lat, ex.center[0]) || !closeTo(lon, ex.center[1]) {
+		t.Errorf(`ToGeo("%s") = (%v, %v); placekey-py returns (%v, %v)`, ex.placekey, lat, lon, ex.center[0], ex.center[1])
+	}
+	if got := ToH3(ex.placekey); got != ex.h3 {
+		t.Errorf(`ToH3("%s") = "%s"; placekey-py returns "%s"`, ex.placekey, got, ex.h3)
+	}
+	if got := FromH3(ex.h3); got != ex.placekey {
+		t.Errorf(`FromH3("%s") = "%s"; placekey-py returns "%s"`, ex.h3, got, ex.placekey)
+	}
+	if got := Distance(ex.placekey, ex.other); math.Abs(got-ex.distance) > 1e-3 {
+		t.Errorf(`Distance("%s", "%s") = %v; placekey-py returns %v`, ex.placekey, ex.other, got, ex.distance)
+	}
+	if !FormatIsValid(ex.valid) || FormatIsValid(ex.invalid) {
+		t.Errorf(`FormatIsValid("%s"), FormatIsValid("%s") = %t, %t; placekey-py returns true, false`,
+			ex.valid, ex.invalid, FormatIsValid(ex.valid), FormatIsValid(ex.invalid))
+	}
+}
+
+func TestGolden(t *testing.T) {
+	if *update {
+		writeGolden(t)
+	}
+
+	b, err := ioutil.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var golden goldenFile
+	if err := json.Unmarshal(b, &golden); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(golden.Source, "placekey-py ") {
+		t.Logf(`%s is not from placekey-py: %s`, goldenPath, golden.Source)
+	}
+
+	for _, e := range golden.Entries {
+		if e.Placekey == "" || len(e.Center) != 2 {
+			t.Errorf(`%s: no expected outputs; run testdata/generate.py`, e.Name)
+			continue
+		}
+		if e.Lat != nil {
+			if got := FromGeo(*e.Lat, *e.Lon); got != e.Placekey {
+				t.Errorf(`%s: FromGeo(%v, %v) = "%s"; wanted "%s"`, e.Name, *e.Lat, *e.Lon, got, e.Placekey)
+			}
+		}
+		if got := FromH3(e.H3); got != e.Placekey {
+			t.Errorf(`%s: FromH3("%s") = "%s"; wanted "%s"`, e.Name, e.H3, got, e.Placekey)
+		}
+		if got, err := FromH3E(e.H3); got != e.Placekey || err != nil {
+			t.Errorf(`%s: FromH3E("%s") = "%s", %v; wanted "%s", nil`, e.Name, e.H3, got, err, e.Placekey)
+		}
+		if got := ToH3(e.Placekey); got != e.H3 {
+			t.Errorf(`%s: ToH3("%s") = "%s"; wanted "%s"`, e.Name, e.Placekey, got, e.H3)
+		}
+		if got := FromH3Int(ToH3Int(e.Placekey)); got != e.Placekey {
+			t.Errorf(`%s: FromH3Int(ToH3Int("%s")) = "%s"`, e.Name, e.Placekey, got)
+		}
+		if !FormatIsValid(e.Placekey) {
+			t.Errorf(`%s: FormatIsValid("%s") = false; wanted true`, e.Name, e.Placekey)
+		}
+		if pk, err := Parse("222-227" + e.Placekey); err != nil || pk.Where() != e.Placekey[1:] || pk.H3() != e.H3 {
+			t.Errorf(`%s: Parse("222-227%s") = "%s", %v`, e.Name, e.Placekey, pk, err)
+		}
+		if lat, lon := ToGeo(e.Placekey); !closeTo(lat, e.Center[0]) || !closeTo(lon, e.Center[1]) {
+			t.Errorf(`%s: ToGeo("%s") = (%v, %v); wanted (%v, %v)`, e.Name, e.Placekey, lat, lon, e.Center[0], e.Center[1])
+		}
+		boundary := ToHexBoundary(e.Placekey)
+		if len(boundary) != len(e.Boundary) {
+			t.Errorf(`%s: ToHexBoundary("%s") has %d vertices; wanted %d`, e.Name, e.Placekey, len(boundary), len(e.Boundary))
+			continue
+		}
+		exceptions := map[int]bool{}
+		for _, i := range goldenBoundaryExceptions[e.Name] {
+			exceptions[i] = true
+		}
+		for i := range boundary {
+			sameLat, sameLon := closeTo(boundary[i][0], e.Boundary[i][0]), closeTo(boundary[i][1], e.Boundary[i][1])
+			if exceptions[i] && (!sameLat || sameLon) {
+				t.Errorf(`%s: ToHexBoundary("%s")[%d] = %v; wanted the known difference in longitude from %v`, e.Name, e.Placekey, i, boundary[i], e.Boundary[i])
+			} else if !exceptions[i] && (!sameLat || !sameLon) {
+				t.Errorf(`%s: ToHexBoundary("%s")[%d] = %v; wanted %v`, e.Name, e.Placekey, i, boundary[i], e.Boundary[i])
+			}
+		}
+		if ring := ToPolygon(e.Placekey)[0]; len(ring) != len(e.Boundary)+1 {
+			t.Errorf(`%s: ToPolygon("%s") has %d points; wanted %d`, e.Name, e.Placekey, len(ring), len(e.Boundary)+1)
+		}
+	}
+
+	for _, d := range golden.Distances {
+		if got := Distance(d.Placekey1, d.Placekey2); math.Abs(got-d.Distance) > 1e-3 {
+			t.Errorf(`Distance("%s", "%s") = %v; wanted %v`, d.Placekey1, d.Placekey2, got, d.Distance)
+		}
+	}
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+// writeGolden rewrites the inputs of the golden file, so that new inputs can be added. Entries
+// that are already in the file keep their outputs; new entries have none until
+// testdata/generate.py fills them in with placekey-py. Outputs are never computed here, so the
+// golden file can't end up agreeing with the code it checks.
+func writeGolden(t *testing.T) {
+	var old goldenFile
+	existing := map[string]goldenEntry{}
+	if b, err := ioutil.ReadFile(goldenPath); err == nil {
+		if err := json.Unmarshal(b, &old); err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range old.Entries {
+			existing[goldenKey(e)] = e
+		}
+	}
+
+	golden := goldenFile{}
+	added := 0
+	add := func(e goldenEntry) {
+		if kept, ok := existing[goldenKey(e)]; ok {
+			e = kept
+		} else {
+			added++
+		}
+		golden.Entries = append(golden.Entries, e)
+	}
+	addPoint := func(name string, lat, lon float64) {
+		add(goldenEntry{Name: name, Lat: &lat, Lon: &lon})
+	}
+
+	for bc := uint64(0); bc < 122; bc++ {
+		center := h3Indexer.ToGeo(0x8001fffffffffff | bc<<45)
+		addPoint("base cell "+strconv.FormatUint(bc, 10), center.Lat, center.Lng)
+	}
+	addPoint("north pole", 90, 0)
+	addPoint("south pole", -90, 0)
+	addPoint("near north pole", 89.9999, 123.4)
+	addPoint("near south pole", -89.9999, -56.7)
+	for _, lat := range []float64{-60, -16.5, 0, 45, 65.5} {
+		addPoint("antimeridian east", lat, 180)
+		addPoint("antimeridian west", lat, -180)
+		addPoint("antimeridian near east", lat, 179.99999)
+		addPoint("antimeridian near west", lat, -179.99999)
+	}
+	addPoint("twin peaks", 37.7371, -122.44283)
+	addPoint("area 51", 37.23712, -115.80187)
+
+	for _, c := range collisionCells() {
+		add(goldenEntry{Name: c.name, H3: h3Indexer.ToString(c.h3)})
+	}
+
+	r := rand.New(rand.NewSource(12))
+	for i := 0; i < 200; i++ {
+		addPoint("random", r.Float64()*180-90, r.Float64()*360-180)
+	}
+
+	// the distances are between consecutive entries, so they only still hold if no entry is new
+	golden.Source, golden.Distances = "inputs only; run testdata/generate.py", nil
+	if added == 0 && len(golden.Entries) == len(old.Entries) {
+		golden.Source, golden.Distances = old.Source, old.Distances
+	}
+
+	lines := []string{}
+	for _, e := range golden.Entries {
+		b, _ := json.Marshal(e)
+		lines = append(lines, "    "+string(b))
+	}
+	dists := []string{}
+	for _, d := range golden.Distances {
+		b, _ := json.Marshal(d)
+		dists = append(dists, "    "+string(b))
+	}
+	source, _ := json.Marshal(golden.Source)
+	out := "{\n  \"source\": " + string(source) + ",\n" +
+		"  \"entries\": [\n" + strings.Join(lines, ",\n") + "\n  ],\n" +
+		"  \"distances\": [\n" + strings.Join(dists, ",\n") + "\n  ]\n}\n"
+	if err := ioutil.WriteFile(filepath.FromSlash(goldenPath), []byte(out), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// goldenKey identifies an entry of the golden file by its name and inputs.
+func goldenKey(e goldenEntry) string {
+	if e.Lat != nil {
+		return e.Name + "|" + strconv.FormatFloat(*e.Lat, 'g', -1, 64) + "|" + strconv.FormatFloat(*e.Lon, 'g', -1, 64)
+	}
+	return e.Name + "|" + e.H3
+}
+
+type collisionCell struct {
+	name string
+	h3   uint64
+}
+
+// collisionCells returns valid cells whose unreplaced codes contain each replacement pattern
+// and each pair of overlapping patterns, at every offset where such a cell exists.
+func collisionCells() []collisionCell {
+	patterns := []string{}
+	for _, kv := range pyReplacementMap {
+		patterns = append(patterns, kv[0])
+	}
+	for _, p := range pyReplacementMap {
+		for _, q := range pyReplacementMap {
+			for k := 1; k < len(p[0]) && k < len(q[0]); k++ {
+				if p[0][len(p[0])-k:] == q[0][:k] {
+					patterns = append(patterns, p[0]+q[0][k:])
+				}
+			}
+		}
+	}
+
+	// leading characters are taken from the codes of real cells so that they decode to valid base cells
+	leads := []string{}
+	for bc := uint64(0); bc < 122; bc++ {
+		center := h3Indexer.ToGeo(0x8001fffffffffff | bc<<45)
+		x := shortenH3Int(h3Indexer.FromGeo(center.Lat, center.Lng, resolution))
+		code := ""
+		for i := 0; i < 9; i++ {
+			code = string(alphabet[x%28]) + code
+			x /= 28
+		}
+		leads = append(leads, code)
+	}
+
+	cells := []collisionCell{}
+	for _, p := range patterns {
+		for offset := 0; offset+len(p) <= 9; offset++ {
+			if h, ok := findCollisionCell(leads, p, offset); ok {
+				cells = append(cells, collisionCell{name: "collision " + p + " at " + strconv.Itoa(offset), h3: h})
+			}
+		}
+	}
+	return cells
+}
+
+// findCollisionCell searches for a valid cell whose unreplaced code has a pattern at an offset.
+func findCollisionCell(leads []string, pattern string, offset int) (uint64, bool) {
+	for _, lead := range leads {
+		prefix := lead[:offset] + pattern
+		var base uint64
+		for _, c := range prefix {
+			base = base*28 + uint64(strings.IndexRune(alphabet, c))
+		}
+		span := uint64(1)
+		for i := len(prefix); i < 9; i++ {
+			base *= 28
+			span *= 28
+		}
+		// the 6 lowest bits of a shortened resolution 10 cell are always set
+		for x, n := base|63, 0; x < base+span && n < 1000; x, n = x+64, n+1 {
+			h := unshortenH3Int(x)
+			if h3Indexer.IsValid(h) && shortenH3Int(h) == x {
+				return h, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,175 @@
+//go:build cgo && !purego
+// +build cgo,!purego
+
+package placekey
+
+import (
+	"math"
+
+	"github.com/uber/h3-go/v4"
+)
+
+// cgoIndexer is the H3 C library through uber/h3-go, used when cgo is available. Build with
+// CGO_ENABLED=0 or the purego tag to use the pure Go port instead.
+type cgoIndexer struct{}
+
+func newIndexer() indexer {
+	return cgoIndexer{}
+}
+
+func (cgoIndexer) FromGeo(lat, lng float64, res int) uint64 {
+	return uint64(h3.LatLngToCell(h3.NewLatLng(lat, lng), res))
+}
+
+func (cgoIndexer) ToGeo(h uint64) LatLng {
+	ll := h3.CellToLatLng(h3.Cell(h))
+	return LatLng{Lat: ll.Lat, Lng: ll.Lng}
+}
+
+func (cgoIndexer) ToGeoBoundary(h uint64) []LatLng {
+	boundary := h3.CellToBoundary(h3.Cell(h))
+	latlngs := make([]LatLng, len(boundary))
+	for i, ll := range boundary {
+		latlngs[i] = LatLng{Lat: ll.Lat, Lng: ll.Lng}
+	}
+	return latlngs
+}
+
+func (cgoIndexer) IsValid(h uint64) bool {
+	return h3.Cell(h).IsValid()
+}
+
+func (cgoIndexer) Resolution(h uint64) int {
+	return h3.Cell(h).Resolution()
+}
+
+func (cgoIndexer) FromString(s string) uint64 {
+	return h3.IndexFromString(s)
+}
+
+func (cgoIndexer) ToString(h uint64) string {
+	return h3.IndexToString(h)
+}
+
+func (cgoIndexer) KRing(h uint64, k int) []uint64 {
+	return fromCells(h3.GridDisk(h3.Cell(h), k))
+}
+
+func (cgoIndexer) KRingDistances(h uint64, k int) [][]uint64 {
+	rings := [][]uint64{}
+	for _, ring := range h3.GridDiskDistances(h3.Cell(h), k) {
+		cells := []uint64{}
+		for _, c := range ring {
+			// the bindings keep the zeros that gridDiskDistances leaves around pentagons
+			if c != 0 {
+				cells = append(cells, uint64(c))
+			}
+		}
+		rings = append(rings, cells)
+	}
+	return rings
+}
+
+func (cgoIndexer) Parent(h uint64, res int) uint64 {
+	return uint64(h3.Cell(h).Parent(res))
+}
+
+func (cgoIndexer) Children(h uint64, res int) []uint64 {
+	// the bindings index into an empty slice when there are no children
+	if h == 0 || res < h3.Cell(h).Resolution() || res > 15 {
+		return nil
+	}
+	return fromCells(h3.Cell(h).Children(res))
+}
+
+func (cgoIndexer) Compact(hs []uint64) []uint64 {
+	// the bindings index into an empty slice when there are no cells
+	if len(hs) == 0 {
+		return nil
+	}
+	return fromCells(h3.CompactCells(toCells(hs)))
+}
+
+func (cgoIndexer) Uncompact(hs []uint64, res int) []uint64 {
+	if len(hs) == 0 {
+		return nil
+	}
+	for _, h := range hs {
+		if !h3.Cell(h).IsValid() || h3.Cell(h).Resolution() > res || res > 15 {
+			return nil
+		}
+	}
+	return fromCells(h3.UncompactCells(toCells(hs), res))
+}
+
+func (cgoIndexer) GridDistance(a, b uint64) int {
+	d := h3.GridDistance(h3.Cell(a), h3.Cell(b))
+	// the bindings return 0 when the distance can't be found
+	if d == 0 && a != b {
+		return -1
+	}
+	return d
+}
+
+func (cgoIndexer) Line(a, b uint64) []uint64 {
+	// the bindings index into an empty slice when the distance can't be found, and leave
+	// zeros in the line when it fails part way
+	if a != b && h3.GridDistance(h3.Cell(a), h3.Cell(b)) == 0 {
+		return nil
+	}
+	line := fromCells(h3.GridPath(h3.Cell(a), h3.Cell(b)))
+	for _, h := range line {
+		if h == 0 {
+			return nil
+		}
+	}
+	return line
+}
+
+func (cgoIndexer) Polyfill(geofence []LatLng, holes [][]LatLng, res int) []uint64 {
+	// the bindings index into an empty slice when the geofence has no width or height, as
+	// polygonToCells can't estimate its size
+	if len(geofence) == 0 {
+		return nil
+	}
+	minLat, maxLat, minLng, maxLng := geofence[0].Lat, geofence[0].Lat, geofence[0].Lng, geofence[0].Lng
+	for _, ll := range geofence {
+		minLat, maxLat = math.Min(minLat, ll.Lat), math.Max(maxLat, ll.Lat)
+		minLng, maxLng = math.Min(minLng, ll.Lng), math.Max(maxLng, ll.Lng)
+	}
+	if minLat == maxLat || minLng == maxLng {
+		return nil
+	}
+	gp := h3.GeoPolygon{GeoLoop: toGeoLoop(geofence)}
+	for _, hole := range holes {
+		gp.Holes = append(gp.Holes, toGeoLoop(hole))
+	}
+	return fromCells(h3.PolygonToCells(gp, res))
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+func toGeoLoop(latlngs []LatLng) h3.GeoLoop {
+	loop := make(h3.GeoLoop, len(latlngs))
+	for i, ll := range latlngs {
+		loop[i] = h3.NewLatLng(ll.Lat, ll.Lng)
+	}
+	return loop
+}
+
+func fromCells(cells []h3.Cell) []uint64 {
+	out := make([]uint64, len(cells))
+	for i, c := range cells {
+		out[i] = uint64(c)
+	}
+	return out
+}
+
+func toCells(hs []uint64) []h3.Cell {
+	out := make([]h3.Cell, len(hs))
+	for i, h := range hs {
+		out[i] = h3.Cell(h)
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+//go:build !cgo || purego
+// +build !cgo purego
+
+package placekey
+
+import h3 "github.com/engelsjk/placekey-go/internal/h3pure"
+
+// pureIndexer is the pure Go port of H3 in internal/h3pure, used when cgo is disabled or the
+// purego tag is set. It produces the same Placekeys as the C library.
+type pureIndexer struct{}
+
+func newIndexer() indexer {
+	return pureIndexer{}
+}
+
+func (pureIndexer) FromGeo(lat, lng float64, res int) uint64 {
+	return uint64(h3.LatLngToCell(h3.NewLatLng(lat, lng), res))
+}
+
+func (pureIndexer) ToGeo(h uint64) LatLng {
+	ll := h3.CellToLatLng(h3.Cell(h))
+	return LatLng{Lat: ll.Lat, Lng: ll.Lng}
+}
+
+func (pureIndexer) ToGeoBoundary(h uint64) []LatLng {
+	boundary := h3.CellToBoundary(h3.Cell(h))
+	latlngs := make([]LatLng, len(boundary))
+	for i, ll := range boundary {
+		latlngs[i] = LatLng{Lat: ll.Lat, Lng: ll.Lng}
+	}
+	return latlngs
+}
+
+func (pureIndexer) IsValid(h uint64) bool {
+	return h3.Cell(h).IsValid()
+}
+
+func (pureIndexer) Resolution(h uint64) int {
+	return h3.Cell(h).Resolution()
+}
+
+func (pureIndexer) FromString(s string) uint64 {
+	return h3.IndexFromString(s)
+}
+
+func (pureIndexer) ToString(h uint64) string {
+	return h3.IndexToString(h)
+}
+
+func (pureIndexer) KRing(h uint64, k int) []uint64 {
+	return fromCells(h3.GridDisk(h3.Cell(h), k))
+}
+
+func (pureIndexer) KRingDistances(h uint64, k int) [][]uint64 {
+	rings := [][]uint64{}
+	for _, ring := range h3.GridDiskDistances(h3.Cell(h), k) {
+		rings = append(rings, fromCells(ring))
+	}
+	return rings
+}
+
+func (pureIndexer) Parent(h uint64, res int) uint64 {
+	return uint64(h3.Cell(h).Parent(res))
+}
+
+func (pureIndexer) Children(h uint64, res int) []uint64 {
+	return fromCells(h3.Cell(h).Children(res))
+}
+
+func (pureIndexer) Compact(hs []uint64) []uint64 {
+	return fromCells(h3.CompactCells(toCells(hs)))
+}
+
+func (pureIndexer) Uncompact(hs []uint64, res int) []uint64 {
+	for _, h := range hs {
+		if !h3.Cell(h).IsValid() {
+			return nil
+		}
+	}
+	return fromCells(h3.UncompactCells(toCells(hs), res))
+}
+
+func (pureIndexer) GridDistance(a, b uint64) int {
+	return h3.GridDistance(h3.Cell(a), h3.Cell(b))
+}
+
+func (pureIndexer) Line(a, b uint64) []uint64 {
+	line := h3.GridPath(h3.Cell(a), h3.Cell(b))
+	if line == nil {
+		return nil
+	}
+	return fromCells(line)
+}
+
+func (pureIndexer) Polyfill(geofence []LatLng, holes [][]LatLng, res int) []uint64 {
+	gp := h3.GeoPolygon{GeoLoop: toGeoLoop(geofence)}
+	for _, hole := range holes {
+		gp.Holes = append(gp.Holes, toGeoLoop(hole))
+	}
+	return fromCells(h3.PolygonToCells(gp, res))
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+func toGeoLoop(latlngs []LatLng) h3.GeoLoop {
+	loop := make(h3.GeoLoop, len(latlngs))
+	for i, ll := range latlngs {
+		loop[i] = h3.NewLatLng(ll.Lat, ll.Lng)
+	}
+	return loop
+}
+
+func fromCells(cells []h3.Cell) []uint64 {
+	out := make([]uint64, len(cells))
+	for i, c := range cells {
+		out[i] = uint64(c)
+	}
+	return out
+}
+
+func toCells(hs []uint64) []h3.Cell {
+	out := make([]h3.Cell, len(hs))
+	for i, h := range hs {
+		out[i] = h3.Cell(h)
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+package placekey
+
+import (
+	"strconv"
+)
+
+// Parent returns the H3 hexadecimal string of the cell containing a Placekey at a coarser
+// resolution, from 0 to 10. It returns an empty string if the Placekey or resolution is invalid.
+func Parent(placekey string, res int) string {
+	h3String, _ := ParentE(placekey, res)
+	return h3String
+}
+
+// ParentE returns the H3 hexadecimal string of the cell containing a Placekey at a coarser
+// resolution, from 0 to 10, returning an error if the Placekey or resolution is invalid.
+func ParentE(placekey string, res int) (string, error) {
+	h3Int, err := ParseWhere(placekey)
+	if err != nil {
+		return "", err
+	}
+	if res < 0 || res > resolution {
+		return "", &Error{Input: strconv.Itoa(res), Err: ErrInvalidResolution}
+	}
+	return h3Indexer.ToString(h3Indexer.Parent(h3Int, res)), nil
+}
+
+// Children returns the Placekeys of the resolution 10 cells inside an H3 cell given as a
+// hexadecimal string. A cell at resolution r has about 7^(10-r) children. It returns nil if
+// the cell is invalid, finer than resolution 10 or has more than MaxChildren children.
+func Children(h3String string) []string {
+	placekeys, _ := ChildrenE(h3String)
+	return placekeys
+}
+
+// ChildrenE returns the Placekeys of the resolution 10 cells inside an H3 cell given as a
+// hexadecimal string, returning an error if the cell is invalid, finer than resolution 10 or
+// has more than MaxChildren children.
+func ChildrenE(h3String string) ([]string, error) {
+	h3Int, err := strconv.ParseUint(h3String, 16, 64)
+	if err != nil {
+		return nil, &Error{Input: h3String, Err: ErrInvalidH3}
+	}
+	if err := checkCoarseH3Int(h3Int); err != nil {
+		return nil, &Error{Input: h3String, Err: err}
+	}
+	if childCount(h3Int) > MaxChildren {
+		return nil, &Error{Input: h3String, Err: ErrTooManyCells}
+	}
+	return encodeH3Ints(h3Indexer.Children(h3Int, resolution)), nil
+}
+
+// Compact merges the Placekeys of full sets of sibling cells into their coarser H3 parent
+// cells, recursively, and returns the resulting H3 integers. Duplicate and invalid Placekeys
+// are left out. Uncompact expands the cells back into Placekeys.
+func Compact(placekeys []string) []uint64 {
+	valid := make([]string, 0, len(placekeys))
+	for _, pk := range placekeys {
+		if _, err := ParseWhere(pk); err == nil {
+			valid = append(valid, pk)
+		}
+	}
+	h3Ints, _ := CompactE(valid)
+	return h3Ints
+}
+
+// CompactE merges the Placekeys of full sets of sibling cells into their coarser H3 parent
+// cells, recursively, and returns the resulting H3 integers, returning an error if a Placekey
+// is invalid. Duplicate Placekeys are left out.
+func CompactE(placekeys []string) ([]uint64, error) {
+	h3Ints := make([]uint64, 0, len(placekeys))
+	seen := make(map[uint64]bool, len(placekeys))
+	for _, pk := range placekeys {
+		h3Int, err := ParseWhere(pk)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[h3Int] {
+			seen[h3Int] = true
+			h3Ints = append(h3Ints, h3Int)
+		}
+	}
+	return h3Indexer.Compact(h3Ints), nil
+}
+
+// Uncompact expands H3 integers at resolution 10 or coarser, such as those returned by
+// Compact, into the Placekeys of their resolution 10 children. Invalid cells, cells finer
+// than resolution 10 and cells that would take the Placekeys over MaxChildren are left out.
+func Uncompact(h3Ints []uint64) []string {
+	valid := make([]uint64, 0, len(h3Ints))
+	count := 0
+	for _, h3Int := range h3Ints {
+		if checkCoarseH3Int(h3Int) == nil && count+childCount(h3Int) <= MaxChildren {
+			valid = append(valid, h3Int)
+			count += childCount(h3Int)
+		}
+	}
+	placekeys, _ := UncompactE(valid)
+	return placekeys
+}
+
+// UncompactE expands H3 integers at resolution 10 or coarser, such as those returned by
+// Compact, into the Placekeys of their resolution 10 children, returning an error if a cell is
+// invalid or finer than resolution 10, or if the cells have more than MaxChildren children.
+func UncompactE(h3Ints []uint64) ([]string, error) {
+	count := 0
+	for _, h3Int := range h3Ints {
+		if err := checkCoarseH3Int(h3Int); err != nil {
+			return nil, &Error{Input: strconv.FormatUint(h3Int, 10), Err: err}
+		}
+		if count += childCount(h3Int); count > MaxChildren {
+			return nil, &Error{Input: strconv.FormatUint(h3Int, 10), Err: ErrTooManyCells}
+		}
+	}
+	return encodeH3Ints(h3Indexer.Uncompact(h3Ints, resolution)), nil
+}
+
+// Parent returns the H3 hexadecimal string of the cell containing the Placekey at a coarser
+// resolution, from 0 to 10, or an empty string if the resolution is out of range or the
+// Placekey is the zero value.
+func (pk Placekey) Parent(res int) string {
+	if res < 0 || res > resolution || pk.IsZero() {
+		return ""
+	}
+	return h3Indexer.ToString(h3Indexer.Parent(pk.h3, res))
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+// check that an H3 integer is a valid cell at the Placekey resolution or coarser.
+func checkCoarseH3Int(h3Int uint64) error {
+	if !h3Indexer.IsValid(h3Int) {
+		return ErrInvalidH3
+	}
+	if h3Indexer.Resolution(h3Int) > resolution {
+		return ErrInvalidResolution
+	}
+	return nil
+}
+
+// childCount returns 7^(10-r) for a cell at resolution r, which is the number of its resolution
+// 10 children, or a little more for a pentagon.
+func childCount(h3Int uint64) int {
+	n := 1
+	for r := h3Indexer.Resolution(h3Int); r < resolution; r++ {
+		n *= 7
+	}
+	return n
+}
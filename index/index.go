@@ -0,0 +1,213 @@
+// Package index is an in-memory spatial index of values keyed by Placekey.
+//
+// Values are bucketed by the where part of their Placekey. Queries walk hexagon rings outward
+// from a Placekey and rank what they find by placekey.Distance, so results are ordered by the
+// distance between Placekey centers. An Index is safe for concurrent use.
+package index
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/engelsjk/placekey-go"
+)
+
+// Result is a value found by a query and the distance in meters from the queried Placekey to
+// its Placekey.
+type Result struct {
+	Placekey string
+	Value    interface{}
+	Distance float64
+}
+
+// minInradius is a lower bound in meters on the distance from the center of a Placekey hexagon to
+// its edges. The smallest hexagons are next to the 12 pentagons, with an inradius of about
+// 45.7 m.
+const minInradius float64 = 45
+
+type entry struct {
+	pk    placekey.Placekey
+	value interface{}
+}
+
+// Index maps Placekeys to values. The zero value is not usable; create one with New.
+type Index struct {
+	mu      sync.RWMutex
+	buckets map[uint64][]entry
+	size    int
+}
+
+// New returns an empty Index.
+func New() *Index {
+	return &Index{buckets: map[uint64][]entry{}}
+}
+
+// Len returns the number of values in the index.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.size
+}
+
+// Add adds a value under a Placekey, returning an error if the Placekey is invalid. A Placekey
+// can hold any number of values.
+func (idx *Index) Add(key string, value interface{}) error {
+	pk, err := placekey.Parse(key)
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.buckets[pk.H3Int()] = append(idx.buckets[pk.H3Int()], entry{pk: pk, value: value})
+	idx.size++
+	return nil
+}
+
+// Remove removes every value added under a Placekey, including its what part, and returns how
+// many were removed. Values are not compared, so there is no way to remove one of several values
+// under the same Placekey; remove them all and add back the ones to keep.
+func (idx *Index) Remove(key string) int {
+	pk, err := placekey.Parse(key)
+	if err != nil {
+		return 0
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	bucket := idx.buckets[pk.H3Int()]
+	kept := bucket[:0]
+	for _, e := range bucket {
+		if e.pk != pk {
+			kept = append(kept, e)
+		}
+	}
+	// clear the tail so removed values can be collected
+	for i := len(kept); i < len(bucket); i++ {
+		bucket[i] = entry{}
+	}
+	removed := len(bucket) - len(kept)
+	if len(kept) == 0 {
+		delete(idx.buckets, pk.H3Int())
+	} else {
+		idx.buckets[pk.H3Int()] = kept
+	}
+	idx.size -= removed
+	return removed
+}
+
+// Nearest returns the n values nearest to a Placekey, nearest first, returning an error if the
+// Placekey is invalid. Values at the same distance are ordered by Placekey.
+func (idx *Index) Nearest(key string, n int) ([]Result, error) {
+	center, err := placekey.Parse(key)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return []Result{}, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	results := []Result{}
+	idx.walk(center, func(bound float64) bool {
+		// the n nearest are settled once n results are nearer than anything left to visit
+		nearer := 0
+		for _, r := range results {
+			if r.Distance < bound {
+				nearer++
+			}
+		}
+		return nearer >= n
+	}, func(d float64, entries []entry) {
+		results = appendResults(results, d, entries)
+	})
+	sortResults(results)
+	if len(results) > n {
+		results = results[:n]
+	}
+	return results, nil
+}
+
+// Within returns the values within a radius in meters of a Placekey, nearest first, returning
+// an error if the Placekey is invalid or the radius is negative or NaN. Values at the same
+// distance are ordered by Placekey.
+func (idx *Index) Within(key string, radius float64) ([]Result, error) {
+	center, err := placekey.Parse(key)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(radius) || radius < 0 {
+		return nil, &placekey.Error{Input: strconv.FormatFloat(radius, 'f', -1, 64), Err: placekey.ErrInvalidRadius}
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	results := []Result{}
+	idx.walk(center, func(bound float64) bool {
+		return bound > radius
+	}, func(d float64, entries []entry) {
+		if d <= radius {
+			results = appendResults(results, d, entries)
+		}
+	})
+	sortResults(results)
+	return results, nil
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+// walk visits the buckets around center a ring of hexagons at a time, nearest ring first,
+// until stop returns true for a lower bound on the distance to every hexagon not yet visited.
+// Along a line from center, the number of steps to the hexagon the line is in grows by at most
+// one for each inradius travelled after leaving the hexagon of center, so ring k and every later
+// ring are at least (k-1)*minInradius away, even next to pentagons and near the poles. Once walking the next ring would take
+// longer than scanning every bucket, the buckets not yet visited are scanned instead. The caller
+// holds the read lock.
+func (idx *Index) walk(center placekey.Placekey, stop func(bound float64) bool, visit func(d float64, entries []entry)) {
+	visited := map[uint64]bool{}
+	walked := 0
+	for k := 0; len(visited) < len(idx.buckets); k++ {
+		ring := center.HexRing(k, false)
+		walked += len(ring)
+		if walked > len(idx.buckets) {
+			for h, entries := range idx.buckets {
+				if !visited[h] {
+					visit(center.Distance(entries[0].pk), entries)
+				}
+			}
+			return
+		}
+
+		if stop(math.Max(0, float64(k-1)) * minInradius) {
+			return
+		}
+		for _, pk := range ring {
+			if entries, ok := idx.buckets[pk.H3Int()]; ok {
+				visited[pk.H3Int()] = true
+				visit(center.Distance(pk), entries)
+			}
+		}
+	}
+}
+
+func appendResults(results []Result, d float64, entries []entry) []Result {
+	for _, e := range entries {
+		results = append(results, Result{Placekey: e.pk.String(), Value: e.value, Distance: d})
+	}
+	return results
+}
+
+// sortResults sorts results by distance and then Placekey, keeping the order in which values
+// were added otherwise.
+func sortResults(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Distance != results[j].Distance {
+			return results[i].Distance < results[j].Distance
+		}
+		return results[i].Placekey < results[j].Placekey
+	})
+}
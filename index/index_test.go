@@ -0,0 +1,206 @@
+package index
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/engelsjk/placekey-go"
+)
+
+// randomIndex returns an index of n values at random points around San Francisco, and the
+// Placekeys of the values in order.
+func randomIndex(n int) (*Index, []string) {
+	return randomIndexAround(37.7371, -122.44283, 0.05, n)
+}
+
+// randomIndexAround returns an index of n values at random points within about spread degrees of
+// latitude of a (latitude, longitude), and the Placekeys of the values in order.
+func randomIndexAround(lat, lon, spread float64, n int) (*Index, []string) {
+	r := rand.New(rand.NewSource(1))
+	idx := New()
+	keys := make([]string, n)
+	for i := range keys {
+		pLat := math.Max(-90, math.Min(90, lat+r.NormFloat64()*spread))
+		pLon := lon + r.NormFloat64()*spread/math.Cos(pLat*math.Pi/180)
+		keys[i] = placekey.FromGeo(pLat, math.Mod(pLon+540, 360)-180)
+		idx.Add(keys[i], i)
+	}
+	return idx, keys
+}
+
+// bruteForce returns every value of keys ranked by distance from a Placekey.
+func bruteForce(center string, keys []string) []Result {
+	results := make([]Result, len(keys))
+	for i, key := range keys {
+		results[i] = Result{Placekey: key, Value: i, Distance: placekey.Distance(center, key)}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Distance != results[j].Distance {
+			return results[i].Distance < results[j].Distance
+		}
+		return results[i].Placekey < results[j].Placekey
+	})
+	return results
+}
+
+func TestNearest(t *testing.T) {
+	idx, keys := randomIndex(5000)
+	centers := []string{"@5vg-82n-kzz", "@5vg-7gq-tvz", "@5ys-rsx-4jv", keys[0]}
+	for _, center := range centers {
+		for _, n := range []int{1, 10, 100} {
+			got, err := idx.Nearest(center, n)
+			if err != nil {
+				t.Fatalf(`Nearest("%s", %d) error = %v; wanted nil`, center, n, err)
+			}
+			want := bruteForce(center, keys)[:n]
+			if len(got) != n {
+				t.Fatalf(`Nearest("%s", %d) has %d results; wanted %d`, center, n, len(got), n)
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Fatalf(`Nearest("%s", %d)[%d] = %+v; wanted %+v`, center, n, i, got[i], want[i])
+				}
+			}
+		}
+	}
+
+	if got, _ := New().Nearest("@5vg-82n-kzz", 3); len(got) != 0 {
+		t.Errorf(`New().Nearest("@5vg-82n-kzz", 3) = %v; wanted no results`, got)
+	}
+	if got, _ := idx.Nearest("@5vg-82n-kzz", 6000); len(got) != 5000 {
+		t.Errorf(`Nearest("@5vg-82n-kzz", 6000) has %d results; wanted 5000`, len(got))
+	}
+	if _, err := idx.Nearest("@123-456-789", 3); !errors.Is(err, placekey.ErrInvalidCharacter) {
+		t.Errorf(`Nearest("@123-456-789", 3) error = %v; wanted %v`, err, placekey.ErrInvalidCharacter)
+	}
+}
+
+// the hexagons around a pentagon and near a pole are distorted the most
+func TestNearestDistorted(t *testing.T) {
+	places := []struct {
+		name     string
+		lat, lon float64
+	}{
+		{"pentagon", 64.70000012793489, 10.53619907546767},
+		{"high latitude", 84.9, 30},
+		{"north pole", 89.99, 0},
+	}
+	for _, p := range places {
+		idx, keys := randomIndexAround(p.lat, p.lon, 0.01, 2000)
+		center := placekey.FromGeo(p.lat, p.lon)
+		all := bruteForce(center, keys)
+		for _, n := range []int{1, 10, 100} {
+			got, _ := idx.Nearest(center, n)
+			for i := range got {
+				if got[i] != all[i] {
+					t.Fatalf(`%s: Nearest("%s", %d)[%d] = %+v; wanted %+v`, p.name, center, n, i, got[i], all[i])
+				}
+			}
+		}
+		for _, radius := range []float64{150, 500, 1000} {
+			got, _ := idx.Within(center, radius)
+			want := 0
+			for want < len(all) && all[want].Distance <= radius {
+				want++
+			}
+			if len(got) != want {
+				t.Fatalf(`%s: Within("%s", %f) has %d results; wanted %d`, p.name, center, radius, len(got), want)
+			}
+			for i := range got {
+				if got[i] != all[i] {
+					t.Fatalf(`%s: Within("%s", %f)[%d] = %+v; wanted %+v`, p.name, center, radius, i, got[i], all[i])
+				}
+			}
+		}
+	}
+}
+
+func TestWithin(t *testing.T) {
+	idx, keys := randomIndex(5000)
+	for _, center := range []string{"@5vg-82n-kzz", "@5vg-7gq-tvz", "@5ys-rsx-4jv"} {
+		for _, radius := range []float64{0, 150, 1000, 5000} {
+			got, err := idx.Within(center, radius)
+			if err != nil {
+				t.Fatalf(`Within("%s", %f) error = %v; wanted nil`, center, radius, err)
+			}
+			want := []Result{}
+			for _, r := range bruteForce(center, keys) {
+				if r.Distance <= radius {
+					want = append(want, r)
+				}
+			}
+			if len(got) != len(want) {
+				t.Fatalf(`Within("%s", %f) has %d results; wanted %d`, center, radius, len(got), len(want))
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Fatalf(`Within("%s", %f)[%d] = %+v; wanted %+v`, center, radius, i, got[i], want[i])
+				}
+			}
+		}
+	}
+
+	if _, err := idx.Within("@5vg-82n-kzz", -1); !errors.Is(err, placekey.ErrInvalidRadius) {
+		t.Errorf(`Within("@5vg-82n-kzz", -1) error = %v; wanted %v`, err, placekey.ErrInvalidRadius)
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	idx := New()
+	for i, key := range []string{"222-227@5vg-82n-kzz", "222-228@5vg-82n-kzz", "@5vg-82n-kzz", "@5vg-82n-k9f"} {
+		if err := idx.Add(key, i); err != nil {
+			t.Fatalf(`Add("%s", %d) error = %v; wanted nil`, key, i, err)
+		}
+	}
+	if err := idx.Add("@5vg-82n", 4); !errors.Is(err, placekey.ErrInvalidWhere) {
+		t.Errorf(`Add("@5vg-82n", 4) error = %v; wanted %v`, err, placekey.ErrInvalidWhere)
+	}
+	if idx.Len() != 4 {
+		t.Fatalf(`Len() = %d; wanted 4`, idx.Len())
+	}
+
+	if got := idx.Remove("222-227@5vg-82n-kzz"); got != 1 {
+		t.Errorf(`Remove("222-227@5vg-82n-kzz") = %d; wanted 1`, got)
+	}
+	if got := idx.Remove("@5vg-82n-k9f"); got != 1 {
+		t.Errorf(`Remove("@5vg-82n-k9f") = %d; wanted 1`, got)
+	}
+	if got := idx.Remove("@5vg-82n-k9f"); got != 0 {
+		t.Errorf(`Remove("@5vg-82n-k9f") = %d; wanted 0`, got)
+	}
+	if idx.Len() != 2 {
+		t.Errorf(`Len() = %d; wanted 2`, idx.Len())
+	}
+
+	got, _ := idx.Nearest("@5vg-82n-k9f", 5)
+	want := []Result{
+		{Placekey: "222-228@5vg-82n-kzz", Value: 1, Distance: got[0].Distance},
+		{Placekey: "@5vg-82n-kzz", Value: 2, Distance: got[0].Distance},
+	}
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf(`Nearest("@5vg-82n-k9f", 5) = %+v; wanted %+v`, got, want)
+	}
+}
+
+func TestConcurrentReads(t *testing.T) {
+	idx, keys := randomIndex(1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%4 == 0 {
+				idx.Add(keys[i], -1)
+				return
+			}
+			if got, err := idx.Nearest(keys[i], 10); err != nil || len(got) != 10 {
+				t.Errorf(`Nearest("%s", 10) = %d results, %v; wanted 10 results`, keys[i], len(got), err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
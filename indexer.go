@@ -0,0 +1,46 @@
+package placekey
+
+// indexer is the H3 backend that converts between coordinates and H3 integers. The encoding
+// logic only calls H3 through it, so that backends can be swapped and faked in tests.
+type indexer interface {
+	// FromGeo returns the H3 integer of the cell containing a (latitude, longitude) at a resolution.
+	FromGeo(lat, lng float64, res int) uint64
+	// ToGeo returns the center of a cell.
+	ToGeo(h uint64) LatLng
+	// ToGeoBoundary returns the vertices of a cell.
+	ToGeoBoundary(h uint64) []LatLng
+	// IsValid returns whether an H3 integer is a valid cell.
+	IsValid(h uint64) bool
+	// Resolution returns the resolution of a cell.
+	Resolution(h uint64) int
+	// FromString returns the H3 integer of an H3 hexadecimal string, or 0 if it is invalid.
+	FromString(s string) uint64
+	// ToString returns the H3 hexadecimal string of an H3 integer.
+	ToString(h uint64) string
+	// KRing returns the cells within k steps of a cell, including the cell.
+	KRing(h uint64, k int) []uint64
+	// KRingDistances returns the cells within k steps of a cell, grouped by their distance from it.
+	KRingDistances(h uint64, k int) [][]uint64
+	// Parent returns the parent of a cell at a coarser resolution, or 0 if the resolution is
+	// out of range or finer than the cell's.
+	Parent(h uint64, res int) uint64
+	// Children returns the children of a cell at a finer resolution, or nil if the resolution is
+	// out of range or coarser than the cell's.
+	Children(h uint64, res int) []uint64
+	// Compact merges full sets of children into their parents, recursively. The cells must share
+	// a resolution and have no duplicates.
+	Compact(hs []uint64) []uint64
+	// Uncompact returns the children at a resolution of every cell, or nil if a cell is invalid
+	// or finer than the resolution.
+	Uncompact(hs []uint64, res int) []uint64
+	// GridDistance returns the number of steps between two cells, or -1 if it can't be found.
+	GridDistance(a, b uint64) int
+	// Line returns the line of cells from a to b, inclusive, each a neighbor of the one before,
+	// or nil if it can't be found.
+	Line(a, b uint64) []uint64
+	// Polyfill returns the cells at a resolution whose centers are inside a polygon.
+	Polyfill(geofence []LatLng, holes [][]LatLng, res int) []uint64
+}
+
+// h3Indexer is the indexer used by the package, chosen at init by build tag.
+var h3Indexer indexer = newIndexer()
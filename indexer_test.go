@@ -0,0 +1,127 @@
+package placekey
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// fakeIndexer is an indexer over a fixed set of cells, each a square 0.01 degrees across.
+type fakeIndexer map[uint64]LatLng
+
+func (f fakeIndexer) FromGeo(lat, lng float64, res int) uint64 {
+	for h, c := range f {
+		if c.Lat == lat && c.Lng == lng {
+			return h
+		}
+	}
+	return 0
+}
+
+func (f fakeIndexer) ToGeo(h uint64) LatLng {
+	return f[h]
+}
+
+func (f fakeIndexer) ToGeoBoundary(h uint64) []LatLng {
+	c := f[h]
+	return []LatLng{
+		{Lat: c.Lat - 0.005, Lng: c.Lng - 0.005}, {Lat: c.Lat - 0.005, Lng: c.Lng + 0.005},
+		{Lat: c.Lat + 0.005, Lng: c.Lng + 0.005}, {Lat: c.Lat + 0.005, Lng: c.Lng - 0.005},
+	}
+}
+
+func (f fakeIndexer) IsValid(h uint64) bool {
+	_, ok := f[h]
+	return ok
+}
+
+func (f fakeIndexer) Resolution(h uint64) int {
+	return resolution
+}
+
+func (f fakeIndexer) FromString(s string) uint64 {
+	h, _ := strconv.ParseUint(s, 16, 64)
+	return h
+}
+
+func (f fakeIndexer) ToString(h uint64) string {
+	return strconv.FormatUint(h, 16)
+}
+
+func (f fakeIndexer) KRing(h uint64, k int) []uint64 {
+	return []uint64{h}
+}
+
+func (f fakeIndexer) KRingDistances(h uint64, k int) [][]uint64 {
+	rings := [][]uint64{{h}}
+	for i := 1; i <= k; i++ {
+		rings = append(rings, []uint64{})
+	}
+	return rings
+}
+
+func (f fakeIndexer) Parent(h uint64, res int) uint64 {
+	return h
+}
+
+func (f fakeIndexer) Children(h uint64, res int) []uint64 {
+	return []uint64{h}
+}
+
+func (f fakeIndexer) Compact(hs []uint64) []uint64 {
+	return hs
+}
+
+func (f fakeIndexer) Uncompact(hs []uint64, res int) []uint64 {
+	return hs
+}
+
+func (f fakeIndexer) GridDistance(a, b uint64) int {
+	return 0
+}
+
+func (f fakeIndexer) Line(a, b uint64) []uint64 {
+	return []uint64{a, b}
+}
+
+func (f fakeIndexer) Polyfill(geofence []LatLng, holes [][]LatLng, res int) []uint64 {
+	return nil
+}
+
+// withIndexer runs a test with h3Indexer replaced.
+func withIndexer(t *testing.T, idx indexer, test func(t *testing.T)) {
+	saved := h3Indexer
+	h3Indexer = idx
+	defer func() { h3Indexer = saved }()
+	test(t)
+}
+
+func TestFakeIndexer(t *testing.T) {
+	// the cells of @dvt-smp-tvz and @5vg-82n-kzz, at made up coordinates
+	fake := fakeIndexer{
+		0x8a754e64992ffff: {Lat: 1, Lng: 2},
+		0x8a2830953157fff: {Lat: 1, Lng: 3},
+	}
+	withIndexer(t, fake, func(t *testing.T) {
+		if got := FromGeo(1, 2); got != "@dvt-smp-tvz" {
+			t.Errorf(`FromGeo(1, 2) = "%s"; wanted "@dvt-smp-tvz"`, got)
+		}
+		if lat, lon := ToGeo("@5vg-82n-kzz"); lat != 1 || lon != 3 {
+			t.Errorf(`ToGeo("@5vg-82n-kzz") = %f, %f; wanted 1, 3`, lat, lon)
+		}
+		if got := ToH3("@dvt-smp-tvz"); got != "8a754e64992ffff" {
+			t.Errorf(`ToH3("@dvt-smp-tvz") = "%s"; wanted "8a754e64992ffff"`, got)
+		}
+		if got := len(ToPolygon("@dvt-smp-tvz")[0]); got != 5 {
+			t.Errorf(`ToPolygon("@dvt-smp-tvz") has %d points; wanted 5`, got)
+		}
+		if got := Distance("@dvt-smp-tvz", "@5vg-82n-kzz"); got < 111000 || got > 112000 {
+			t.Errorf(`Distance("@dvt-smp-tvz", "@5vg-82n-kzz") = %f; wanted about 111195`, got)
+		}
+
+		// validity comes from the indexer, so a real cell unknown to the fake is invalid
+		if _, err := ParseWhere("@5vg-7gq-tjv"); !errors.Is(err, ErrInvalidH3) {
+			t.Errorf(`ParseWhere("@5vg-7gq-tjv") error = %v; wanted %v`, err, ErrInvalidH3)
+		}
+	})
+}
@@ -0,0 +1,596 @@
+package h3pure
+
+import "math"
+
+// directions is the order in which the sides of a k-ring are traversed.
+var directions = [6]direction{jAxesDigit, jkAxesDigit, kAxesDigit, ikAxesDigit, iAxesDigit, ijAxesDigit}
+
+// nextRingDirection is the direction taken from the last cell of a k-ring to the next ring.
+const nextRingDirection = iAxesDigit
+
+// newDigitII and newDigitIII are the new digit when moving in a direction from an old digit at
+// a Class II and Class III resolution, and newAdjustmentII and newAdjustmentIII are the
+// direction in which to continue moving at the next coarser resolution.
+var (
+	newDigitII = [7][7]direction{
+		{centerDigit, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit},
+		{kAxesDigit, iAxesDigit, jkAxesDigit, ijAxesDigit, ikAxesDigit, jAxesDigit, centerDigit},
+		{jAxesDigit, jkAxesDigit, kAxesDigit, iAxesDigit, ijAxesDigit, centerDigit, ikAxesDigit},
+		{jkAxesDigit, ijAxesDigit, iAxesDigit, ikAxesDigit, centerDigit, kAxesDigit, jAxesDigit},
+		{iAxesDigit, ikAxesDigit, ijAxesDigit, centerDigit, jAxesDigit, jkAxesDigit, kAxesDigit},
+		{ikAxesDigit, jAxesDigit, centerDigit, kAxesDigit, jkAxesDigit, ijAxesDigit, iAxesDigit},
+		{ijAxesDigit, centerDigit, ikAxesDigit, jAxesDigit, kAxesDigit, iAxesDigit, jkAxesDigit},
+	}
+	newAdjustmentII = [7][7]direction{
+		{centerDigit, centerDigit, centerDigit, centerDigit, centerDigit, centerDigit, centerDigit},
+		{centerDigit, kAxesDigit, centerDigit, kAxesDigit, centerDigit, ikAxesDigit, centerDigit},
+		{centerDigit, centerDigit, jAxesDigit, jkAxesDigit, centerDigit, centerDigit, jAxesDigit},
+		{centerDigit, kAxesDigit, jkAxesDigit, jkAxesDigit, centerDigit, centerDigit, centerDigit},
+		{centerDigit, centerDigit, centerDigit, centerDigit, iAxesDigit, iAxesDigit, ijAxesDigit},
+		{centerDigit, ikAxesDigit, centerDigit, centerDigit, iAxesDigit, ikAxesDigit, centerDigit},
+		{centerDigit, centerDigit, jAxesDigit, centerDigit, ijAxesDigit, centerDigit, ijAxesDigit},
+	}
+	newDigitIII = [7][7]direction{
+		{centerDigit, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit},
+		{kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit, centerDigit},
+		{jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit, centerDigit, kAxesDigit},
+		{jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit, centerDigit, kAxesDigit, jAxesDigit},
+		{iAxesDigit, ikAxesDigit, ijAxesDigit, centerDigit, kAxesDigit, jAxesDigit, jkAxesDigit},
+		{ikAxesDigit, ijAxesDigit, centerDigit, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit},
+		{ijAxesDigit, centerDigit, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit},
+	}
+	newAdjustmentIII = [7][7]direction{
+		{centerDigit, centerDigit, centerDigit, centerDigit, centerDigit, centerDigit, centerDigit},
+		{centerDigit, kAxesDigit, centerDigit, jkAxesDigit, centerDigit, kAxesDigit, centerDigit},
+		{centerDigit, centerDigit, jAxesDigit, jAxesDigit, centerDigit, centerDigit, ijAxesDigit},
+		{centerDigit, jkAxesDigit, jAxesDigit, jkAxesDigit, centerDigit, centerDigit, centerDigit},
+		{centerDigit, centerDigit, centerDigit, centerDigit, iAxesDigit, ikAxesDigit, iAxesDigit},
+		{centerDigit, kAxesDigit, centerDigit, centerDigit, ikAxesDigit, ikAxesDigit, centerDigit},
+		{centerDigit, centerDigit, ijAxesDigit, centerDigit, iAxesDigit, centerDigit, ijAxesDigit},
+	}
+)
+
+func maxKringSize(k int) int {
+	return 3*k*(k+1) + 1
+}
+
+// kRing fills out with the cells within k steps of origin. Cells are placed in spiral order if
+// no pentagon is encountered, and otherwise at hashed positions with zeros in between.
+func kRing(origin h3Index, k int, out []h3Index) {
+	kRingDistances(origin, k, out, make([]int, len(out)))
+}
+
+// kRingDistances is kRing that also fills distances with the distance of each cell from origin.
+func kRingDistances(origin h3Index, k int, out []h3Index, distances []int) {
+	if !hexRangeDistances(origin, k, out, distances) {
+		for i := range out {
+			out[i] = 0
+			distances[i] = 0
+		}
+		kRingInternal(origin, k, out, distances, 0)
+	}
+}
+
+// kRingInternal adds origin and its neighbors to out, used as a hash set, recursing until k.
+func kRingInternal(origin h3Index, k int, out []h3Index, distances []int, curK int) {
+	if origin == 0 {
+		return
+	}
+
+	// put origin in the output array
+	off := int(uint64(origin) % uint64(len(out)))
+	for out[off] != 0 && out[off] != origin {
+		off = (off + 1) % len(out)
+	}
+
+	// we either got a free slot in the hash set or hit a duplicate, which might need to be
+	// processed again if we got here on a longer path before
+	if out[off] == origin && distances[off] <= curK {
+		return
+	}
+
+	out[off] = origin
+	distances[off] = curK
+
+	// base case: reached an index k away from the origin
+	if curK >= k {
+		return
+	}
+
+	// recurse to all neighbors in no particular order
+	for i := 0; i < 6; i++ {
+		rotations := 0
+		kRingInternal(neighborRotations(origin, directions[i], &rotations), k, out, distances, curK+1)
+	}
+}
+
+// hexRangeDistances fills out with the cells within k steps of origin in spiral order,
+// returning false if a pentagon was encountered.
+func hexRangeDistances(origin h3Index, k int, out []h3Index, distances []int) bool {
+	idx := 0
+	out[idx] = origin
+	distances[idx] = 0
+	idx++
+
+	if isPentagon(origin) {
+		return false
+	}
+
+	// current ring, side of the ring and position on the side
+	ring := 1
+	dir := 0
+	i := 0
+
+	// number of 60 degree ccw rotations to perform on the direction based on which faces
+	// have been crossed
+	rotations := 0
+
+	for ring <= k {
+		if dir == 0 && i == 0 {
+			// not put in the output set as it will be done at the end of this ring
+			origin = neighborRotations(origin, nextRingDirection, &rotations)
+			if origin == 0 || isPentagon(origin) {
+				return false
+			}
+		}
+
+		origin = neighborRotations(origin, directions[dir], &rotations)
+		if origin == 0 {
+			return false
+		}
+		out[idx] = origin
+		distances[idx] = ring
+		idx++
+
+		i++
+		// check if end of this side of the k-ring
+		if i == ring {
+			i = 0
+			dir++
+			// check if end of this ring
+			if dir == 6 {
+				dir = 0
+				ring++
+			}
+		}
+
+		if isPentagon(origin) {
+			return false
+		}
+	}
+	return true
+}
+
+// neighborRotations returns the neighbor of origin in a direction, after rotating the
+// direction by rotations, and updates rotations for the faces crossed. It returns 0 if the
+// neighbor is in the deleted k-axes subsequence of a pentagon.
+func neighborRotations(origin h3Index, dir direction, rotations *int) h3Index {
+	out := origin
+
+	for i := 0; i < *rotations; i++ {
+		dir = dir.rotate60ccw()
+	}
+
+	newRotations := 0
+	oldBaseCell := getBaseCell(out)
+	oldLeadingDigit := leadingNonZeroDigit(out)
+
+	// adjust the indexing digits and, if needed, the base cell
+	r := getResolution(out) - 1
+	for {
+		if r == -1 {
+			setBaseCell(&out, baseCellNeighbors[oldBaseCell][dir])
+			newRotations = baseCellNeighbor60CCWRots[oldBaseCell][dir]
+
+			if getBaseCell(out) == invalidBaseCell {
+				// adjust for the deleted k vertex at the base cell level; this edge actually
+				// borders a different neighbor
+				setBaseCell(&out, baseCellNeighbors[oldBaseCell][ikAxesDigit])
+				newRotations = baseCellNeighbor60CCWRots[oldBaseCell][ikAxesDigit]
+
+				// perform the adjustment for the k-subsequence we're skipping over
+				out = rotate60ccw(out)
+				*rotations = *rotations + 1
+			}
+			break
+		}
+
+		oldDigit := getIndexDigit(out, r+1)
+		var nextDir direction
+		if isResClassIII(r + 1) {
+			setIndexDigit(&out, r+1, newDigitII[oldDigit][dir])
+			nextDir = newAdjustmentII[oldDigit][dir]
+		} else {
+			setIndexDigit(&out, r+1, newDigitIII[oldDigit][dir])
+			nextDir = newAdjustmentIII[oldDigit][dir]
+		}
+
+		if nextDir == centerDigit {
+			// no more adjustment to perform
+			break
+		}
+		dir = nextDir
+		r--
+	}
+
+	newBaseCell := getBaseCell(out)
+	if isBaseCellPentagon(newBaseCell) {
+		alreadyAdjustedKSubsequence := false
+
+		// force rotation out of missing k-axes sub-sequence
+		if leadingNonZeroDigit(out) == kAxesDigit {
+			if oldBaseCell != newBaseCell {
+				// we traversed into the deleted k subsequence of a pentagon base cell; rotate
+				// out of it depending on how we got here, checking for a cw/ccw offset face
+				if baseCellIsCwOffset(newBaseCell, baseCellData[oldBaseCell].homeFijk.face) {
+					out = rotate60cw(out)
+				} else {
+					out = rotate60ccw(out)
+				}
+				alreadyAdjustedKSubsequence = true
+			} else {
+				// we traversed into the deleted k subsequence from within the same pentagon
+				// base cell
+				switch oldLeadingDigit {
+				case jkAxesDigit:
+					// rotate out of the deleted k subsequence, also changing the direction
+					out = rotate60ccw(out)
+					*rotations = *rotations + 1
+				case ikAxesDigit:
+					out = rotate60cw(out)
+					*rotations = *rotations + 5
+				default:
+					// the k direction is deleted from here
+					return 0
+				}
+			}
+		}
+
+		for i := 0; i < newRotations; i++ {
+			out = rotatePent60ccw(out)
+		}
+
+		// account for differing orientation of the base cells
+		if oldBaseCell != newBaseCell {
+			if isBaseCellPolarPentagon(newBaseCell) {
+				// 'polar' base cells behave differently because they have all i neighbors
+				if oldBaseCell != 118 && oldBaseCell != 8 && leadingNonZeroDigit(out) != jkAxesDigit {
+					*rotations = *rotations + 1
+				}
+			} else if leadingNonZeroDigit(out) == ikAxesDigit && !alreadyAdjustedKSubsequence {
+				// account for distortion introduced to the 5 neighbor by the deleted k subsequence
+				*rotations = *rotations + 1
+			}
+		}
+	} else {
+		for i := 0; i < newRotations; i++ {
+			out = rotate60ccw(out)
+		}
+	}
+
+	*rotations = (*rotations + newRotations) % 6
+	return out
+}
+
+// bbox is a geographic bounding box in radians. It crosses the antimeridian if east < west.
+type bbox struct {
+	north, south, east, west float64
+}
+
+func (b bbox) isTransmeridian() bool {
+	return b.east < b.west
+}
+
+func (b bbox) contains(p geoCoord) bool {
+	if p.lat < b.south || p.lat > b.north {
+		return false
+	}
+	if b.isTransmeridian() {
+		return p.lon >= b.west || p.lon <= b.east
+	}
+	return p.lon >= b.west && p.lon <= b.east
+}
+
+// bboxFromGeofence returns the bounding box of a loop.
+func bboxFromGeofence(loop []geoCoord) bbox {
+	if len(loop) == 0 {
+		return bbox{}
+	}
+
+	b := bbox{south: math.MaxFloat64, west: math.MaxFloat64, north: -math.MaxFloat64, east: -math.MaxFloat64}
+	minPosLon := math.MaxFloat64
+	maxNegLon := -math.MaxFloat64
+	isTransmeridian := false
+
+	for i, coord := range loop {
+		next := loop[(i+1)%len(loop)]
+		lat, lon := coord.lat, coord.lon
+		if lat < b.south {
+			b.south = lat
+		}
+		if lon < b.west {
+			b.west = lon
+		}
+		if lat > b.north {
+			b.north = lat
+		}
+		if lon > b.east {
+			b.east = lon
+		}
+
+		// save the min positive and max negative longitude for use in the transmeridian case
+		if lon > 0 && lon < minPosLon {
+			minPosLon = lon
+		}
+		if lon < 0 && lon > maxNegLon {
+			maxNegLon = lon
+		}
+
+		// check for arcs > 180 degrees longitude, flagging as transmeridian
+		if math.Abs(lon-next.lon) > math.Pi {
+			isTransmeridian = true
+		}
+	}
+
+	// swap east and west if transmeridian
+	if isTransmeridian {
+		b.east = maxNegLon
+		b.west = minPosLon
+	}
+	return b
+}
+
+// pointInsideGeofence returns whether a point is inside a loop by casting a ray east.
+func pointInsideGeofence(loop []geoCoord, box bbox, coord geoCoord) bool {
+	// fail fast if we're outside the bounding box
+	if !box.contains(coord) {
+		return false
+	}
+	isTransmeridian := box.isTransmeridian()
+	contains := false
+
+	lat := coord.lat
+	lng := normalizeLon(coord.lon, isTransmeridian)
+
+	for i := range loop {
+		a := loop[i]
+		b := loop[(i+1)%len(loop)]
+
+		// the ray casting algo requires the second point to always be higher than the first
+		if a.lat > b.lat {
+			a, b = b, a
+		}
+
+		// if the latitude matches exactly, the ray would pass through the vertex twice on
+		// successive segments, so adjust the latitude northward
+		if lat == a.lat || lat == b.lat {
+			lat += dblEpsilon
+		}
+
+		// if we're totally above or below the latitude ranges, the ray cannot intersect
+		if lat < a.lat || lat > b.lat {
+			continue
+		}
+
+		aLng := normalizeLon(a.lon, isTransmeridian)
+		bLng := normalizeLon(b.lon, isTransmeridian)
+
+		// in case a point exactly matches, bias westerly to decide tiebreakers
+		if aLng == lng || bLng == lng {
+			lng -= dblEpsilon
+		}
+
+		// the longitude of the point on the segment at the latitude of the point
+		ratio := (lat - a.lat) / (b.lat - a.lat)
+		testLng := normalizeLon(aLng+(bLng-aLng)*ratio, isTransmeridian)
+
+		if testLng > lng {
+			contains = !contains
+		}
+	}
+	return contains
+}
+
+// dblEpsilon is the difference between 1 and the next larger float64.
+const dblEpsilon float64 = 2.220446049250313080847263336181640625e-16
+
+func normalizeLon(lon float64, isTransmeridian bool) float64 {
+	if isTransmeridian && lon < 0 {
+		return lon + twoPi
+	}
+	return lon
+}
+
+// polygonToCellsBuffer is added to the estimated number of cells of a polygon, since tracing
+// a small polygon near an icosahedron edge at an odd resolution can find a few more.
+const polygonToCellsBuffer = 12
+
+// polyfill returns the cells whose centers are inside a polygon and outside its holes. The
+// cells are traced along the loops and grown inward from there, and are returned in a hash
+// table with zeros at its empty slots. It returns nil if the polygon is degenerate.
+func polyfill(geofence []geoCoord, holes [][]geoCoord, res int) []h3Index {
+	bboxes := make([]bbox, len(holes)+1)
+	bboxes[0] = bboxFromGeofence(geofence)
+	for i, hole := range holes {
+		bboxes[i+1] = bboxFromGeofence(hole)
+	}
+
+	numHexagons, ok := bboxes[0].hexEstimate(res)
+	if !ok {
+		return nil
+	}
+	// the estimate assumes that there are usually fewer vertices than hexagons
+	totalVerts := len(geofence)
+	for _, hole := range holes {
+		totalVerts += len(hole)
+	}
+	if numHexagons < totalVerts {
+		numHexagons = totalVerts
+	}
+	numHexagons += polygonToCellsBuffer
+
+	out := make([]h3Index, numHexagons)
+	search := make([]h3Index, 0, numHexagons)
+	found := make([]h3Index, numHexagons)
+
+	// trace the hexagons along the geofence and the holes, using found to dedupe them
+	for _, loop := range append([][]geoCoord{geofence}, holes...) {
+		if search, ok = edgeHexagons(loop, res, search, found); !ok {
+			return nil
+		}
+	}
+
+	// test the neighbors of each searched hexagon, and search from the ones inside the polygon
+	// until no new hexagons are found
+	next := make([]h3Index, 0, numHexagons)
+	ring := make([]h3Index, maxKringSize(1))
+	for len(search) > 0 {
+		for _, searchHex := range search {
+			for i := range ring {
+				ring[i] = InvalidH3Index
+			}
+			kRing(searchHex, 1, ring)
+			for _, hex := range ring {
+				if hex == InvalidH3Index {
+					// a pentagon only has 5 neighbors
+					continue
+				}
+
+				loc, ok := hashSlot(out, hex)
+				if !ok {
+					return nil
+				}
+				if out[loc] == hex {
+					continue
+				}
+
+				if !pointInsidePolygon(geofence, holes, bboxes, faceIjkToGeo(h3ToFaceIjk(hex), res)) {
+					continue
+				}
+				out[loc] = hex
+				next = append(next, hex)
+			}
+		}
+		search, next = next, search[:0]
+	}
+	return out
+}
+
+// edgeHexagons appends the cells along the edges of a loop, sampled at about the cell diameter,
+// to search, using found as a hash table to skip duplicates.
+func edgeHexagons(loop []geoCoord, res int, search, found []h3Index) ([]h3Index, bool) {
+	for i, origin := range loop {
+		destination := loop[(i+1)%len(loop)]
+		numHexesEstimate, ok := lineHexEstimate(origin, destination, res)
+		if !ok {
+			return search, false
+		}
+		n := float64(numHexesEstimate)
+		for j := 0; j < numHexesEstimate; j++ {
+			interpolate := geoCoord{
+				lat: origin.lat*(n-float64(j))/n + destination.lat*float64(j)/n,
+				lon: origin.lon*(n-float64(j))/n + destination.lon*float64(j)/n,
+			}
+			if math.IsNaN(interpolate.lat) || math.IsInf(interpolate.lat, 0) ||
+				math.IsNaN(interpolate.lon) || math.IsInf(interpolate.lon, 0) {
+				return search, false
+			}
+			pointHex := faceIjkToH3(geoToFaceIjk(interpolate, res), res)
+
+			loc, ok := hashSlot(found, pointHex)
+			if !ok {
+				return search, false
+			}
+			if found[loc] == pointHex {
+				continue
+			}
+			found[loc] = pointHex
+			search = append(search, pointHex)
+		}
+	}
+	return search, true
+}
+
+// hashSlot returns the slot of an open addressing hash table that holds a cell, or the empty
+// slot where it belongs. It returns false if the table is full.
+func hashSlot(table []h3Index, h h3Index) (int, bool) {
+	loc := int(uint64(h) % uint64(len(table)))
+	for loopCount := 0; table[loc] != InvalidH3Index; loopCount++ {
+		if loopCount > len(table) {
+			return 0, false
+		}
+		if table[loc] == h {
+			break
+		}
+		loc = (loc + 1) % len(table)
+	}
+	return loc, true
+}
+
+// pentagonRadiusKm returns the distance from the center of the first pentagon at a resolution
+// to its first vertex, as the radius of the most distorted cells.
+func pentagonRadiusKm(res int) float64 {
+	h := h3Index(h3Init)
+	setMode(&h, hexagonMode)
+	setResolution(&h, res)
+	setBaseCell(&h, 4)
+	for r := 1; r <= res; r++ {
+		setIndexDigit(&h, r, centerDigit)
+	}
+	fijk := h3ToFaceIjk(h)
+	return greatCircleDistanceKm(faceIjkToGeo(fijk, res), faceIjkToGeoBoundary(fijk, res, true)[0])
+}
+
+// hexEstimate returns an estimate of the number of cells in a bbox, or false if it is degenerate.
+func (b bbox) hexEstimate(res int) (int, bool) {
+	// the area of the pentagon is the maximally distorted area possible; it is shrunk by 20%
+	// in case the bbox perfectly bounds a pentagon
+	r := pentagonRadiusKm(res)
+	pentagonAreaKm2 := 0.8 * (2.59807621135 * r * r)
+
+	p1 := geoCoord{lat: b.north, lon: b.east}
+	p2 := geoCoord{lat: b.south, lon: b.west}
+	d := greatCircleDistanceKm(p1, p2)
+	lngDiff := math.Abs(p1.lon - p2.lon)
+	latDiff := math.Abs(p1.lat - p2.lat)
+	if lngDiff == 0 || latDiff == 0 {
+		return 0, false
+	}
+	ratio := math.Max(lngDiff, latDiff) / math.Min(lngDiff, latDiff)
+	// clamped to 3, as higher ratios rapidly drag the estimate to zero
+	a := d * d / math.Min(3, ratio)
+
+	estimate := math.Ceil(a / pentagonAreaKm2)
+	if math.IsNaN(estimate) || math.IsInf(estimate, 0) {
+		return 0, false
+	}
+	if estimate == 0 {
+		return 1, true
+	}
+	return int(estimate), true
+}
+
+// lineHexEstimate returns an estimate of the number of cells along a great circle arc.
+func lineHexEstimate(origin, destination geoCoord, res int) (int, bool) {
+	dist := greatCircleDistanceKm(origin, destination)
+	distCeil := math.Ceil(dist / (2 * pentagonRadiusKm(res)))
+	if math.IsNaN(distCeil) || math.IsInf(distCeil, 0) {
+		return 0, false
+	}
+	if distCeil == 0 {
+		return 1, true
+	}
+	return int(distCeil), true
+}
+
+func pointInsidePolygon(geofence []geoCoord, holes [][]geoCoord, bboxes []bbox, coord geoCoord) bool {
+	if !pointInsideGeofence(geofence, bboxes[0], coord) {
+		return false
+	}
+	for i, hole := range holes {
+		if pointInsideGeofence(hole, bboxes[i+1], coord) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,285 @@
+package h3pure
+
+import "math"
+
+// coordIJK is a coordinate in an ijk+ hexagon grid: a set of three axes 120 degrees apart,
+// normalized so that no component is negative and at least one is zero.
+type coordIJK struct {
+	i, j, k int
+}
+
+// direction is an H3 digit, the ijk+ axes direction from the center of a parent cell.
+type direction int
+
+const (
+	centerDigit direction = iota
+	kAxesDigit
+	jAxesDigit
+	jkAxesDigit
+	iAxesDigit
+	ikAxesDigit
+	ijAxesDigit
+	invalidDigit
+)
+
+const numDigits = invalidDigit
+
+// unitVecs is the unit vector of each direction.
+var unitVecs = [numDigits]coordIJK{
+	{0, 0, 0},
+	{0, 0, 1},
+	{0, 1, 0},
+	{0, 1, 1},
+	{1, 0, 0},
+	{1, 0, 1},
+	{1, 1, 0},
+}
+
+// hex2dToCoordIJK returns the ijk+ coordinates of the hex containing a point in a hex2d grid.
+func hex2dToCoordIJK(v vec2d) coordIJK {
+	h := coordIJK{}
+
+	// quantize into the ij system and then normalize
+	a1 := math.Abs(v.x)
+	a2 := math.Abs(v.y)
+
+	// first do a reverse conversion
+	x2 := divLD(a2, ldSqrt3_2)
+	x1 := a1 + x2/2
+
+	// check if we have the center of a hex
+	m1 := int(x1)
+	m2 := int(x2)
+
+	// otherwise round correctly
+	r1 := x1 - float64(m1)
+	r2 := x2 - float64(m2)
+
+	if r1 < 0.5 {
+		if lessDD(r1, dd(ldOneThird)) {
+			if lessDD(r2, twoSum(1, r1).mul(dd{0.5, 0})) {
+				h.i, h.j = m1, m2
+			} else {
+				h.i, h.j = m1, m2+1
+			}
+		} else {
+			if lessDD(r2, twoSum(1, -r1)) {
+				h.j = m2
+			} else {
+				h.j = m2 + 1
+			}
+			if !lessDD(r2, twoSum(1, -r1)) && r2 < 2*r1 {
+				h.i = m1 + 1
+			} else {
+				h.i = m1
+			}
+		}
+	} else {
+		if lessDD(r1, dd(ldTwoThirds)) {
+			if lessDD(r2, twoSum(1, -r1)) {
+				h.j = m2
+			} else {
+				h.j = m2 + 1
+			}
+			if greaterDD(r2, twoSum(2*r1, -1)) && lessDD(r2, twoSum(1, -r1)) {
+				h.i = m1
+			} else {
+				h.i = m1 + 1
+			}
+		} else {
+			if r2 < r1/2 {
+				h.i, h.j = m1+1, m2
+			} else {
+				h.i, h.j = m1+1, m2+1
+			}
+		}
+	}
+
+	// now fold across the axes if necessary
+	if v.x < 0 {
+		if h.j%2 == 0 {
+			axisi := h.j / 2
+			diff := h.i - axisi
+			h.i = h.i - 2*diff
+		} else {
+			axisi := (h.j + 1) / 2
+			diff := h.i - axisi
+			h.i = h.i - (2*diff + 1)
+		}
+	}
+	if v.y < 0 {
+		h.i = h.i - (2*h.j+1)/2
+		h.j = -h.j
+	}
+
+	h.normalize()
+	return h
+}
+
+// toHex2d returns the center point of the hex in a hex2d grid.
+func (c coordIJK) toHex2d() vec2d {
+	i := c.i - c.k
+	j := c.j - c.k
+	return vec2d{x: float64(i) - 0.5*float64(j), y: mulLD(float64(j), ldSqrt3_2)}
+}
+
+func (c coordIJK) add(o coordIJK) coordIJK {
+	return coordIJK{c.i + o.i, c.j + o.j, c.k + o.k}
+}
+
+func (c coordIJK) sub(o coordIJK) coordIJK {
+	return coordIJK{c.i - o.i, c.j - o.j, c.k - o.k}
+}
+
+func (c coordIJK) scale(factor int) coordIJK {
+	return coordIJK{c.i * factor, c.j * factor, c.k * factor}
+}
+
+// normalize removes negative components and the common minimum from a coordinate.
+func (c *coordIJK) normalize() {
+	// remove any negative values
+	if c.i < 0 {
+		c.j -= c.i
+		c.k -= c.i
+		c.i = 0
+	}
+	if c.j < 0 {
+		c.i -= c.j
+		c.k -= c.j
+		c.j = 0
+	}
+	if c.k < 0 {
+		c.i -= c.k
+		c.j -= c.k
+		c.k = 0
+	}
+
+	// remove the min value if needed
+	min := c.i
+	if c.j < min {
+		min = c.j
+	}
+	if c.k < min {
+		min = c.k
+	}
+	if min > 0 {
+		c.i -= min
+		c.j -= min
+		c.k -= min
+	}
+}
+
+// unitIjkToDigit returns the direction of a unit vector, or invalidDigit.
+func unitIjkToDigit(c coordIJK) direction {
+	c.normalize()
+	for d := centerDigit; d < numDigits; d++ {
+		if c == unitVecs[d] {
+			return d
+		}
+	}
+	return invalidDigit
+}
+
+// upAp7 moves a coordinate to the containing cell of the next coarser aperture 7 grid
+// (Class III, ccw).
+func (c *coordIJK) upAp7() {
+	i := c.i - c.k
+	j := c.j - c.k
+	c.i = int(math.Round(float64(3*i-j) / 7))
+	c.j = int(math.Round(float64(i+2*j) / 7))
+	c.k = 0
+	c.normalize()
+}
+
+// upAp7r moves a coordinate to the containing cell of the next coarser aperture 7 grid
+// (Class II, cw).
+func (c *coordIJK) upAp7r() {
+	i := c.i - c.k
+	j := c.j - c.k
+	c.i = int(math.Round(float64(2*i+j) / 7))
+	c.j = int(math.Round(float64(3*j-i) / 7))
+	c.k = 0
+	c.normalize()
+}
+
+// downAp7 moves a coordinate to the center cell of the next finer aperture 7 grid (Class III, ccw).
+func (c *coordIJK) downAp7() {
+	c.transform(coordIJK{3, 0, 1}, coordIJK{1, 3, 0}, coordIJK{0, 1, 3})
+}
+
+// downAp7r moves a coordinate to the center cell of the next finer aperture 7 grid (Class II, cw).
+func (c *coordIJK) downAp7r() {
+	c.transform(coordIJK{3, 1, 0}, coordIJK{0, 3, 1}, coordIJK{1, 0, 3})
+}
+
+// downAp3 moves a coordinate to the center cell of the next finer aperture 3 grid (ccw).
+func (c *coordIJK) downAp3() {
+	c.transform(coordIJK{2, 0, 1}, coordIJK{1, 2, 0}, coordIJK{0, 1, 2})
+}
+
+// downAp3r moves a coordinate to the center cell of the next finer aperture 3 grid (cw).
+func (c *coordIJK) downAp3r() {
+	c.transform(coordIJK{2, 1, 0}, coordIJK{0, 2, 1}, coordIJK{1, 0, 2})
+}
+
+// rotate60ccw rotates a coordinate 60 degrees counter-clockwise.
+func (c *coordIJK) rotate60ccw() {
+	c.transform(coordIJK{1, 1, 0}, coordIJK{0, 1, 1}, coordIJK{1, 0, 1})
+}
+
+// rotate60cw rotates a coordinate 60 degrees clockwise.
+func (c *coordIJK) rotate60cw() {
+	c.transform(coordIJK{1, 0, 1}, coordIJK{1, 1, 0}, coordIJK{0, 1, 1})
+}
+
+// transform maps a coordinate onto the given images of the i, j and k unit vectors.
+func (c *coordIJK) transform(iVec, jVec, kVec coordIJK) {
+	*c = iVec.scale(c.i).add(jVec.scale(c.j)).add(kVec.scale(c.k))
+	c.normalize()
+}
+
+// neighbor moves a coordinate to its neighbor in a direction.
+func (c *coordIJK) neighbor(d direction) {
+	if d > centerDigit && d < numDigits {
+		*c = c.add(unitVecs[d])
+		c.normalize()
+	}
+}
+
+func (d direction) rotate60ccw() direction {
+	switch d {
+	case kAxesDigit:
+		return ikAxesDigit
+	case ikAxesDigit:
+		return iAxesDigit
+	case iAxesDigit:
+		return ijAxesDigit
+	case ijAxesDigit:
+		return jAxesDigit
+	case jAxesDigit:
+		return jkAxesDigit
+	case jkAxesDigit:
+		return kAxesDigit
+	default:
+		return d
+	}
+}
+
+func (d direction) rotate60cw() direction {
+	switch d {
+	case kAxesDigit:
+		return jkAxesDigit
+	case jkAxesDigit:
+		return jAxesDigit
+	case jAxesDigit:
+		return ijAxesDigit
+	case ijAxesDigit:
+		return iAxesDigit
+	case iAxesDigit:
+		return ikAxesDigit
+	case ikAxesDigit:
+		return kAxesDigit
+	default:
+		return d
+	}
+}
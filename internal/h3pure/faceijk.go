@@ -0,0 +1,365 @@
+package h3pure
+
+// faceIJK is an ijk+ coordinate on one of the faces of the icosahedron.
+type faceIJK struct {
+	face  int
+	coord coordIJK
+}
+
+// faceOrientIJK is the orientation of a neighboring face: its number, the resolution 0
+// translation relative to the primary face and the number of ccw 60 degree rotations.
+type faceOrientIJK struct {
+	face      int
+	translate coordIJK
+	ccwRot60  int
+}
+
+// quadrants of a face, used to index faceNeighbors
+const (
+	ij = 1
+	ki = 2
+	jk = 3
+)
+
+// overage results of adjustOverageClassII
+const (
+	noOverage = iota
+	faceEdge
+	newFace
+)
+
+// maxDimByCIIres is the maximum coordinate of a face at each Class II resolution.
+var maxDimByCIIres = [...]int{2, -1, 14, -1, 98, -1, 686, -1, 4802, -1, 33614, -1, 235298, -1, 1647086, -1, 11529602}
+
+// unitScaleByCIIres is the scale of a resolution 0 unit vector at each Class II resolution.
+var unitScaleByCIIres = [...]int{1, -1, 7, -1, 49, -1, 343, -1, 2401, -1, 16807, -1, 117649, -1, 823543, -1, 5764801}
+
+// vertices of an origin-centered cell on an aperture 33r substrate grid for Class II
+// resolutions and 33r7r for Class III resolutions, listed ccw from the i-axes
+var (
+	vertsCII  = [numHexVerts]coordIJK{{2, 1, 0}, {1, 2, 0}, {0, 2, 1}, {0, 1, 2}, {1, 0, 2}, {2, 0, 1}}
+	vertsCIII = [numHexVerts]coordIJK{{5, 4, 0}, {1, 5, 0}, {0, 5, 4}, {0, 1, 5}, {4, 0, 5}, {5, 0, 1}}
+)
+
+func isResClassIII(res int) bool {
+	return res%2 == 1
+}
+
+// geoToFaceIjk returns the face and ijk+ coordinates of the cell containing a point.
+func geoToFaceIjk(g geoCoord, res int) faceIJK {
+	face, v := geoToHex2d(g, res)
+	return faceIJK{face: face, coord: hex2dToCoordIJK(v)}
+}
+
+// geoToHex2d returns the closest face to a point and the point's hex2d coordinates on it.
+func geoToHex2d(g geoCoord, res int) (int, vec2d) {
+	v3d := geoToVec3d(g)
+
+	// determine the icosahedron face
+	face := 0
+	sqd := pointSquareDist(faceCenterPoint[0], v3d)
+	for f := 1; f < numIcosaFaces; f++ {
+		sqdT := pointSquareDist(faceCenterPoint[f], v3d)
+		if sqdT < sqd {
+			face = f
+			sqd = sqdT
+		}
+	}
+
+	// cos(r) = 1 - 2 * sin^2(r/2) = 1 - 2 * (sqd / 4) = 1 - sqd/2
+	r := acos(1 - sqd/2)
+	if belowEpsilon(r) {
+		return face, vec2d{}
+	}
+
+	// now have face and r, now find CCW theta from CII i-axis
+	theta := posAngleRads(faceAxesAzRadsCII[face][0] - posAngleRads(geoAzimuthRads(faceCenterGeo[face], g)))
+
+	// adjust theta for Class III (odd resolutions)
+	if isResClassIII(res) {
+		theta = posAngleRads(subLD(theta, ldAp7RotRads))
+	}
+
+	// perform gnomonic scaling of r, then scale for the current resolution length u
+	r = tan(r)
+	r = divLD(r, ldRes0UGnomonic)
+	for i := 0; i < res; i++ {
+		r = mulLD(r, ldSqrt7)
+	}
+
+	sinTheta, cosTheta := sinCos(theta)
+	return face, vec2d{x: r * cosTheta, y: r * sinTheta}
+}
+
+// hex2dToGeo returns the point at hex2d coordinates on a face. Substrate grids are scaled
+// down by an aperture 3 and, for Class III, already rotated.
+func hex2dToGeo(v vec2d, face, res int, substrate bool) geoCoord {
+	r := v.mag()
+	if belowEpsilon(r) {
+		return faceCenterGeo[face]
+	}
+
+	theta := atan2(v.y, v.x)
+
+	// scale for current resolution length u
+	for i := 0; i < res; i++ {
+		r = divLD(r, ldSqrt7)
+	}
+
+	// scale accordingly if this is a substrate grid
+	if substrate {
+		r /= 3
+		if isResClassIII(res) {
+			r = divLD(r, ldSqrt7)
+		}
+	}
+
+	r = mulLD(r, ldRes0UGnomonic)
+
+	// perform inverse gnomonic scaling of r
+	r = atan(r)
+
+	// adjust theta for Class III; a substrate grid has already been adjusted
+	if !substrate && isResClassIII(res) {
+		theta = posAngleRads(addLD(theta, ldAp7RotRads))
+	}
+
+	// find theta as an azimuth
+	theta = posAngleRads(faceAxesAzRadsCII[face][0] - theta)
+
+	// now find the point at (r,theta) from the face center
+	return geoAzDistanceRads(faceCenterGeo[face], theta, r)
+}
+
+// faceIjkToGeo returns the center point of a cell.
+func faceIjkToGeo(h faceIJK, res int) geoCoord {
+	return hex2dToGeo(h.coord.toHex2d(), h.face, res, false)
+}
+
+// faceIjkToGeoBoundary returns the vertices of a cell, including the points where its edges
+// cross icosahedron edges.
+func faceIjkToGeoBoundary(h faceIJK, res int, isPentagon bool) []geoCoord {
+	if isPentagon {
+		return faceIjkPentToGeoBoundary(h, res)
+	}
+
+	verts := vertsCII
+	if isResClassIII(res) {
+		verts = vertsCIII
+	}
+
+	// adjust the center point to be in an aperture 33r substrate grid
+	centerIJK := h
+	centerIJK.coord.downAp3()
+	centerIJK.coord.downAp3r()
+
+	// if res is Class III we need to add a cw aperture 7 to get to icosahedral Class II
+	adjRes := res
+	if isResClassIII(res) {
+		centerIJK.coord.downAp7r()
+		adjRes++
+	}
+
+	// translate the origin cell vertices to the center point
+	fijkVerts := [numHexVerts]faceIJK{}
+	for v := 0; v < numHexVerts; v++ {
+		fijkVerts[v].face = centerIJK.face
+		fijkVerts[v].coord = centerIJK.coord.add(verts[v])
+		fijkVerts[v].coord.normalize()
+	}
+
+	// convert each vertex to lat/lon, adjusting the face of each vertex as appropriate and
+	// introducing edge-crossing vertices as needed
+	g := make([]geoCoord, 0, maxCellBndryVerts)
+	lastFace := -1
+	lastOverage := noOverage
+	for vert := 0; vert < numHexVerts+1; vert++ {
+		v := vert % numHexVerts
+
+		fijk := fijkVerts[v]
+		overage := adjustOverageClassII(&fijk, adjRes, false, true)
+
+		// Each face of the icosahedron is a different projection plane, so if an edge of the
+		// cell crosses an icosahedron edge an additional vertex is introduced at the
+		// intersection. Class II cell edges have vertices on the face edge instead.
+		if isResClassIII(res) && vert > 0 && fijk.face != lastFace && lastOverage != faceEdge {
+			// find hex2d of the two vertexes on original face
+			lastV := (v + 5) % numHexVerts
+			orig2d0 := fijkVerts[lastV].coord.toHex2d()
+			orig2d1 := fijkVerts[v].coord.toHex2d()
+
+			face2 := lastFace
+			if lastFace == centerIJK.face {
+				face2 = fijk.face
+			}
+			edge0, edge1 := faceEdge2d(adjacentFaceDir[centerIJK.face][face2], adjRes)
+
+			// an intersection at a cell vertex needs no additional vertex
+			inter := v2dIntersect(orig2d0, orig2d1, edge0, edge1)
+			if !orig2d0.almostEquals(inter) && !orig2d1.almostEquals(inter) {
+				g = append(g, hex2dToGeo(inter, centerIJK.face, adjRes, true))
+			}
+		}
+
+		// vert == numHexVerts is only used to test for an intersection on the last edge
+		if vert < numHexVerts {
+			g = append(g, hex2dToGeo(fijk.coord.toHex2d(), fijk.face, adjRes, true))
+		}
+
+		lastFace = fijk.face
+		lastOverage = overage
+	}
+	return g
+}
+
+// faceIjkPentToGeoBoundary returns the vertices of a pentagon, including the points where
+// its edges cross icosahedron edges.
+func faceIjkPentToGeoBoundary(h faceIJK, res int) []geoCoord {
+	verts := vertsCII
+	if isResClassIII(res) {
+		verts = vertsCIII
+	}
+
+	// adjust the center point to be in an aperture 33r substrate grid
+	centerIJK := h
+	centerIJK.coord.downAp3()
+	centerIJK.coord.downAp3r()
+
+	// if res is Class III we need to add a cw aperture 7 to get to icosahedral Class II
+	adjRes := res
+	if isResClassIII(res) {
+		centerIJK.coord.downAp7r()
+		adjRes++
+	}
+
+	// translate the origin cell vertices to the center point
+	fijkVerts := [numPentVerts]faceIJK{}
+	for v := 0; v < numPentVerts; v++ {
+		fijkVerts[v].face = centerIJK.face
+		fijkVerts[v].coord = centerIJK.coord.add(verts[v])
+		fijkVerts[v].coord.normalize()
+	}
+
+	g := make([]geoCoord, 0, maxCellBndryVerts)
+	lastFijk := faceIJK{}
+	for vert := 0; vert < numPentVerts+1; vert++ {
+		v := vert % numPentVerts
+
+		fijk := fijkVerts[v]
+		for adjustOverageClassII(&fijk, adjRes, false, true) == newFace {
+		}
+
+		// all Class III pentagon edges cross icosa edges; Class II pentagons have vertices on
+		// the edge instead
+		if isResClassIII(res) && vert > 0 {
+			// find hex2d of the two vertexes on the last face
+			tmpFijk := fijk
+
+			orig2d0 := lastFijk.coord.toHex2d()
+
+			currentToLastDir := adjacentFaceDir[tmpFijk.face][lastFijk.face]
+			fijkOrient := faceNeighbors[tmpFijk.face][currentToLastDir]
+
+			// rotate and translate for adjacent face
+			tmpFijk.face = fijkOrient.face
+			for i := 0; i < fijkOrient.ccwRot60; i++ {
+				tmpFijk.coord.rotate60ccw()
+			}
+			tmpFijk.coord = tmpFijk.coord.add(fijkOrient.translate.scale(unitScaleByCIIres[adjRes] * 3))
+			tmpFijk.coord.normalize()
+
+			orig2d1 := tmpFijk.coord.toHex2d()
+
+			edge0, edge1 := faceEdge2d(adjacentFaceDir[tmpFijk.face][fijk.face], adjRes)
+			inter := v2dIntersect(orig2d0, orig2d1, edge0, edge1)
+			g = append(g, hex2dToGeo(inter, tmpFijk.face, adjRes, true))
+		}
+
+		// vert == numPentVerts is only used to test for an intersection on the last edge
+		if vert < numPentVerts {
+			g = append(g, hex2dToGeo(fijk.coord.toHex2d(), fijk.face, adjRes, true))
+		}
+
+		lastFijk = fijk
+	}
+	return g
+}
+
+// faceEdge2d returns the hex2d endpoints of the edge of a face in a quadrant on a substrate grid.
+func faceEdge2d(quadrant, adjRes int) (vec2d, vec2d) {
+	maxDim := float64(maxDimByCIIres[adjRes])
+	v0 := vec2d{x: 3 * maxDim, y: 0}
+	v1 := vec2d{x: -1.5 * maxDim, y: mulLD(3*maxDim, ldSqrt3_2)}
+	v2 := vec2d{x: -1.5 * maxDim, y: mulLD(-3*maxDim, ldSqrt3_2)}
+	switch quadrant {
+	case ij:
+		return v0, v1
+	case jk:
+		return v1, v2
+	default:
+		return v2, v0
+	}
+}
+
+// adjustOverageClassII moves a Class II coordinate that lies beyond the edge of its face onto
+// the adjacent face, returning whether it was on the edge or moved to a new face.
+func adjustOverageClassII(fijk *faceIJK, res int, pentLeading4, substrate bool) int {
+	overage := noOverage
+
+	ijk := &fijk.coord
+
+	// get the maximum dimension value; scale if a substrate grid
+	maxDim := maxDimByCIIres[res]
+	if substrate {
+		maxDim *= 3
+	}
+
+	// check for overage
+	if substrate && ijk.i+ijk.j+ijk.k == maxDim {
+		overage = faceEdge
+	} else if ijk.i+ijk.j+ijk.k > maxDim {
+		overage = newFace
+
+		var fijkOrient faceOrientIJK
+		if ijk.k > 0 {
+			if ijk.j > 0 {
+				fijkOrient = faceNeighbors[fijk.face][jk]
+			} else {
+				fijkOrient = faceNeighbors[fijk.face][ki]
+
+				// adjust for the pentagonal missing sequence
+				if pentLeading4 {
+					// translate origin to center of pentagon, rotate to adjust for the missing
+					// sequence and translate the origin back to the center of the triangle
+					origin := coordIJK{maxDim, 0, 0}
+					tmp := ijk.sub(origin)
+					tmp.rotate60cw()
+					*ijk = tmp.add(origin)
+				}
+			}
+		} else {
+			fijkOrient = faceNeighbors[fijk.face][ij]
+		}
+
+		fijk.face = fijkOrient.face
+
+		// rotate and translate for adjacent face
+		for i := 0; i < fijkOrient.ccwRot60; i++ {
+			ijk.rotate60ccw()
+		}
+
+		unitScale := unitScaleByCIIres[res]
+		if substrate {
+			unitScale *= 3
+		}
+		*ijk = ijk.add(fijkOrient.translate.scale(unitScale))
+		ijk.normalize()
+
+		// overage points on pentagon boundaries can end up on edges
+		if substrate && ijk.i+ijk.j+ijk.k == maxDim {
+			overage = faceEdge
+		}
+	}
+
+	return overage
+}
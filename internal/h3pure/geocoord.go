@@ -0,0 +1,191 @@
+package h3pure
+
+import "math"
+
+// twoPi is M_2PI cast to double, as the polygon algorithms use it.
+const twoPi float64 = 6.28318530717958647692528676655900576839433
+
+const (
+	deg2rad float64 = math.Pi / 180.0
+	rad2deg float64 = 180.0 / math.Pi
+)
+
+// The constants below are long doubles in the H3 C library. They are kept in extended
+// precision so that arithmetic with them rounds as it does in C.
+var (
+	ld2Pi           = newLongDouble("6.28318530717958647692528676655900576839433")
+	ldSqrt3_2       = newLongDouble("0.8660254037844386467637231707529361834714")
+	ldAp7RotRads    = newLongDouble("0.333473172251832115336090755351601070065900389")
+	ldRes0UGnomonic = newLongDouble("0.38196601125010500003")
+	ldSqrt7         = newLongDouble("2.6457513110645905905016157536392604257102")
+	ldEarthRadiusKm = newLongDouble("6371.007180918475")
+	ldEpsilon       = newLongDouble("0.0000000000000001")
+	ldOneThird      = newLongDouble("0.33333333333333333333333333333333333333333")
+	ldTwoThirds     = newLongDouble("0.66666666666666666666666666666666666666667")
+)
+
+const (
+	numIcosaFaces     = 20
+	numBaseCells      = 122
+	numHexVerts       = 6
+	numPentVerts      = 5
+	maxCellBndryVerts = 10
+	invalidBaseCell   = 127
+	maxFaceCoord      = 2
+	maxH3Res          = 15
+)
+
+// geoCoord is a (latitude, longitude) in radians.
+type geoCoord struct {
+	lat, lon float64
+}
+
+type vec2d struct {
+	x, y float64
+}
+
+type vec3d struct {
+	x, y, z float64
+}
+
+func geoToVec3d(g geoCoord) vec3d {
+	sinLat, r := sinCos(g.lat)
+	sinLon, cosLon := sinCos(g.lon)
+	return vec3d{x: cosLon * r, y: sinLon * r, z: sinLat}
+}
+
+func pointSquareDist(v1, v2 vec3d) float64 {
+	return (v1.x-v2.x)*(v1.x-v2.x) + (v1.y-v2.y)*(v1.y-v2.y) + (v1.z-v2.z)*(v1.z-v2.z)
+}
+
+func (v vec2d) mag() float64 {
+	return math.Sqrt(v.x*v.x + v.y*v.y)
+}
+
+// almostEquals returns whether two points are within single precision epsilon of each other.
+func (v vec2d) almostEquals(w vec2d) bool {
+	return math.Abs(v.x-w.x) < fltEpsilon && math.Abs(v.y-w.y) < fltEpsilon
+}
+
+// fltEpsilon is the difference between 1 and the next larger float32.
+const fltEpsilon float64 = 1.1920928955078125e-7
+
+// v2dIntersect returns the intersection of the lines through p0, p1 and through p2, p3.
+func v2dIntersect(p0, p1, p2, p3 vec2d) vec2d {
+	s1 := vec2d{x: p1.x - p0.x, y: p1.y - p0.y}
+	s2 := vec2d{x: p3.x - p2.x, y: p3.y - p2.y}
+	t := (s2.x*(p0.y-p2.y) - s2.y*(p0.x-p2.x)) / (-s2.x*s1.y + s1.x*s2.y)
+	return vec2d{x: p0.x + t*s1.x, y: p0.y + t*s1.y}
+}
+
+// belowEpsilon returns whether x is less than the C library's EPSILON.
+func belowEpsilon(x float64) bool {
+	return lessDD(x, dd(ldEpsilon))
+}
+
+// posAngleRads normalizes an angle in radians to [0, 2pi).
+func posAngleRads(rads float64) float64 {
+	tmp := rads
+	if rads < 0 {
+		tmp = addLD(rads, ld2Pi)
+	}
+	if !lessDD(rads, dd(ld2Pi)) {
+		tmp = subLD(tmp, ld2Pi)
+	}
+	return tmp
+}
+
+func constrainLng(lng float64) float64 {
+	for lng > math.Pi {
+		lng = lng - 2*math.Pi
+	}
+	for lng < -math.Pi {
+		lng = lng + 2*math.Pi
+	}
+	return lng
+}
+
+// greatCircleDistanceRads returns the great circle distance in radians between two points,
+// with the haversine formula.
+func greatCircleDistanceRads(a, b geoCoord) float64 {
+	sinLat := sin((b.lat - a.lat) / 2)
+	sinLng := sin((b.lon - a.lon) / 2)
+
+	A := sinLat*sinLat + cos(a.lat)*cos(b.lat)*sinLng*sinLng
+
+	return 2 * atan2(math.Sqrt(A), math.Sqrt(1-A))
+}
+
+func greatCircleDistanceKm(a, b geoCoord) float64 {
+	return mulLD(greatCircleDistanceRads(a, b), ldEarthRadiusKm)
+}
+
+// geoAzimuthRads returns the azimuth in radians from p1 to p2.
+func geoAzimuthRads(p1, p2 geoCoord) float64 {
+	sinLat1, cosLat1 := sinCos(p1.lat)
+	sinLat2, cosLat2 := sinCos(p2.lat)
+	sinDLon, cosDLon := sinCos(p2.lon - p1.lon)
+	return atan2(cosLat2*sinDLon, cosLat1*sinLat2-sinLat1*cosLat2*cosDLon)
+}
+
+// geoAzDistanceRads returns the point at an azimuth and distance in radians from p1.
+func geoAzDistanceRads(p1 geoCoord, az, distance float64) geoCoord {
+	if belowEpsilon(distance) {
+		return p1
+	}
+
+	p2 := geoCoord{}
+	az = posAngleRads(az)
+
+	// check for due north/south azimuth
+	if belowEpsilon(az) || belowEpsilon(math.Abs(az-math.Pi)) {
+		if belowEpsilon(az) {
+			p2.lat = p1.lat + distance
+		} else {
+			p2.lat = p1.lat - distance
+		}
+		if belowEpsilon(math.Abs(p2.lat - math.Pi/2)) {
+			p2.lat, p2.lon = math.Pi/2, 0
+		} else if belowEpsilon(math.Abs(p2.lat + math.Pi/2)) {
+			p2.lat, p2.lon = -math.Pi/2, 0
+		} else {
+			p2.lon = constrainLng(p1.lon)
+		}
+		return p2
+	}
+
+	sinLat1, cosLat1 := sinCos(p1.lat)
+	sinDist, cosDist := sinCos(distance)
+	sinAz, cosAz := sinCos(az)
+	sinlat := sinLat1*cosDist + cosLat1*sinDist*cosAz
+	if sinlat > 1 {
+		sinlat = 1
+	}
+	if sinlat < -1 {
+		sinlat = -1
+	}
+	p2.lat = asin(sinlat)
+	if belowEpsilon(math.Abs(p2.lat - math.Pi/2)) {
+		p2.lat, p2.lon = math.Pi/2, 0
+	} else if belowEpsilon(math.Abs(p2.lat + math.Pi/2)) {
+		p2.lat, p2.lon = -math.Pi/2, 0
+	} else {
+		sinLat2, cosLat2 := sinCos(p2.lat)
+		sinlon := sinAz * sinDist / cosLat2
+		coslon := (cosDist - sinLat1*sinLat2) / cosLat1 / cosLat2
+		if sinlon > 1 {
+			sinlon = 1
+		}
+		if sinlon < -1 {
+			sinlon = -1
+		}
+		if coslon > 1 {
+			coslon = 1
+		}
+		if coslon < -1 {
+			coslon = -1
+		}
+		p2.lon = constrainLng(p1.lon + atan2(sinlon, coslon))
+	}
+	return p2
+}
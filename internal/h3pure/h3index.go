@@ -0,0 +1,476 @@
+package h3pure
+
+// h3Index is an H3 index, kept unsigned so that its bits can be manipulated directly.
+type h3Index uint64
+
+// bit layout of an H3 index
+const (
+	highBitOffset  = 63
+	modeOffset     = 59
+	reservedOffset = 56
+	baseCellOffset = 45
+	resOffset      = 52
+	perDigitOffset = 3
+	hexagonMode    = 1
+
+	highBitMask  uint64 = 1 << highBitOffset
+	modeMask     uint64 = 15 << modeOffset
+	reservedMask uint64 = 7 << reservedOffset
+	baseCellMask uint64 = 127 << baseCellOffset
+	resMask      uint64 = 15 << resOffset
+	digitMask    uint64 = 7
+
+	// h3Init is an index with every digit set to 7 and all other bits unset.
+	h3Init uint64 = 35184372088831
+)
+
+// baseCellOrient is a base cell and the number of ccw 60 degree rotations into its orientation.
+type baseCellOrient struct {
+	baseCell int
+	ccwRot60 int
+}
+
+// baseCellInfo is the home face and coordinates of a base cell, whether it is a pentagon and
+// the faces with cw offset rotations for pentagons.
+type baseCellInfo struct {
+	homeFijk     faceIJK
+	isPentagon   bool
+	cwOffsetPent [2]int
+}
+
+func getHighBit(h h3Index) int {
+	return int((uint64(h) & highBitMask) >> highBitOffset)
+}
+
+func getReservedBits(h h3Index) int {
+	return int((uint64(h) & reservedMask) >> reservedOffset)
+}
+
+func getMode(h h3Index) int {
+	return int((uint64(h) & modeMask) >> modeOffset)
+}
+
+func setMode(h *h3Index, mode int) {
+	*h = h3Index(uint64(*h)&^modeMask | uint64(mode)<<modeOffset)
+}
+
+func getBaseCell(h h3Index) int {
+	return int((uint64(h) & baseCellMask) >> baseCellOffset)
+}
+
+func setBaseCell(h *h3Index, baseCell int) {
+	*h = h3Index(uint64(*h)&^baseCellMask | uint64(baseCell)<<baseCellOffset)
+}
+
+func getResolution(h h3Index) int {
+	return int((uint64(h) & resMask) >> resOffset)
+}
+
+func setResolution(h *h3Index, res int) {
+	*h = h3Index(uint64(*h)&^resMask | uint64(res)<<resOffset)
+}
+
+func getIndexDigit(h h3Index, res int) direction {
+	return direction((uint64(h) >> (uint(maxH3Res-res) * perDigitOffset)) & digitMask)
+}
+
+func setIndexDigit(h *h3Index, res int, d direction) {
+	shift := uint(maxH3Res-res) * perDigitOffset
+	*h = h3Index(uint64(*h)&^(digitMask<<shift) | uint64(d)<<shift)
+}
+
+func isBaseCellPentagon(baseCell int) bool {
+	return baseCellData[baseCell].isPentagon
+}
+
+func isBaseCellPolarPentagon(baseCell int) bool {
+	return baseCell == 4 || baseCell == 117
+}
+
+func baseCellIsCwOffset(baseCell, testFace int) bool {
+	return baseCellData[baseCell].cwOffsetPent[0] == testFace || baseCellData[baseCell].cwOffsetPent[1] == testFace
+}
+
+func isPentagon(h h3Index) bool {
+	return isBaseCellPentagon(getBaseCell(h)) && leadingNonZeroDigit(h) == centerDigit
+}
+
+// leadingNonZeroDigit returns the first non-zero digit of an index, or centerDigit.
+func leadingNonZeroDigit(h h3Index) direction {
+	for r := 1; r <= getResolution(h); r++ {
+		if d := getIndexDigit(h, r); d != centerDigit {
+			return d
+		}
+	}
+	return centerDigit
+}
+
+// rotatePent60ccw rotates a pentagon index 60 degrees ccw, skipping the deleted k-axes sequence.
+func rotatePent60ccw(h h3Index) h3Index {
+	foundFirstNonZeroDigit := false
+	for r, res := 1, getResolution(h); r <= res; r++ {
+		setIndexDigit(&h, r, getIndexDigit(h, r).rotate60ccw())
+
+		// adjust for deleted k-axes sequence if necessary
+		if !foundFirstNonZeroDigit && getIndexDigit(h, r) != centerDigit {
+			foundFirstNonZeroDigit = true
+			if leadingNonZeroDigit(h) == kAxesDigit {
+				h = rotate60ccw(h)
+			}
+		}
+	}
+	return h
+}
+
+// rotatePent60cw rotates a pentagon index 60 degrees cw, skipping the deleted k-axes sequence.
+func rotatePent60cw(h h3Index) h3Index {
+	foundFirstNonZeroDigit := false
+	for r, res := 1, getResolution(h); r <= res; r++ {
+		setIndexDigit(&h, r, getIndexDigit(h, r).rotate60cw())
+
+		// adjust for deleted k-axes sequence if necessary
+		if !foundFirstNonZeroDigit && getIndexDigit(h, r) != centerDigit {
+			foundFirstNonZeroDigit = true
+			if leadingNonZeroDigit(h) == kAxesDigit {
+				h = rotate60cw(h)
+			}
+		}
+	}
+	return h
+}
+
+func rotate60ccw(h h3Index) h3Index {
+	for r, res := 1, getResolution(h); r <= res; r++ {
+		setIndexDigit(&h, r, getIndexDigit(h, r).rotate60ccw())
+	}
+	return h
+}
+
+func rotate60cw(h h3Index) h3Index {
+	for r, res := 1, getResolution(h); r <= res; r++ {
+		setIndexDigit(&h, r, getIndexDigit(h, r).rotate60cw())
+	}
+	return h
+}
+
+// faceIjkToH3 returns the index of the cell at ijk+ coordinates on a face.
+func faceIjkToH3(fijk faceIJK, res int) h3Index {
+	h := h3Index(h3Init)
+	setMode(&h, hexagonMode)
+	setResolution(&h, res)
+
+	// check for res 0/base cell
+	if res == 0 {
+		if fijk.coord.i > maxFaceCoord || fijk.coord.j > maxFaceCoord || fijk.coord.k > maxFaceCoord {
+			return InvalidH3Index
+		}
+		setBaseCell(&h, faceIjkBaseCells[fijk.face][fijk.coord.i][fijk.coord.j][fijk.coord.k].baseCell)
+		return h
+	}
+
+	// build the index from finest res up, finding the base cell coordinates on this face
+	fijkBC := fijk
+	ijk := &fijkBC.coord
+	for r := res - 1; r >= 0; r-- {
+		lastIJK := *ijk
+		var lastCenter coordIJK
+		if isResClassIII(r + 1) {
+			// rotate ccw
+			ijk.upAp7()
+			lastCenter = *ijk
+			lastCenter.downAp7()
+		} else {
+			// rotate cw
+			ijk.upAp7r()
+			lastCenter = *ijk
+			lastCenter.downAp7r()
+		}
+
+		diff := lastIJK.sub(lastCenter)
+		diff.normalize()
+		setIndexDigit(&h, r+1, unitIjkToDigit(diff))
+	}
+
+	if fijkBC.coord.i > maxFaceCoord || fijkBC.coord.j > maxFaceCoord || fijkBC.coord.k > maxFaceCoord {
+		return InvalidH3Index
+	}
+
+	// lookup the correct base cell
+	orient := faceIjkBaseCells[fijkBC.face][fijkBC.coord.i][fijkBC.coord.j][fijkBC.coord.k]
+	baseCell := orient.baseCell
+	setBaseCell(&h, baseCell)
+
+	// rotate if necessary to get canonical base cell orientation for this base cell
+	numRots := orient.ccwRot60
+	if isBaseCellPentagon(baseCell) {
+		// force rotation out of missing k-axes sub-sequence
+		if leadingNonZeroDigit(h) == kAxesDigit {
+			// check for a cw/ccw offset face; default is ccw
+			if baseCellIsCwOffset(baseCell, fijkBC.face) {
+				h = rotate60cw(h)
+			} else {
+				h = rotate60ccw(h)
+			}
+		}
+		for i := 0; i < numRots; i++ {
+			h = rotatePent60ccw(h)
+		}
+	} else {
+		for i := 0; i < numRots; i++ {
+			h = rotate60ccw(h)
+		}
+	}
+
+	return h
+}
+
+// h3ToFaceIjkWithInitializedFijk converts the digits of an index into ijk+ coordinates on the
+// home face of its base cell, returning whether the cell could lie on an adjacent face.
+func h3ToFaceIjkWithInitializedFijk(h h3Index, fijk *faceIJK) bool {
+	ijk := &fijk.coord
+	res := getResolution(h)
+
+	// center base cell hierarchy is entirely on this face
+	possibleOverage := true
+	if !isBaseCellPentagon(getBaseCell(h)) && (res == 0 || *ijk == coordIJK{}) {
+		possibleOverage = false
+	}
+
+	for r := 1; r <= res; r++ {
+		if isResClassIII(r) {
+			// Class III == rotate ccw
+			ijk.downAp7()
+		} else {
+			// Class II == rotate cw
+			ijk.downAp7r()
+		}
+		ijk.neighbor(getIndexDigit(h, r))
+	}
+
+	return possibleOverage
+}
+
+// h3ToFaceIjk returns the face and ijk+ coordinates of a cell.
+func h3ToFaceIjk(h h3Index) faceIJK {
+	baseCell := getBaseCell(h)
+
+	// adjust for the pentagonal missing sequence; all of sub-sequence 5 needs to be adjusted
+	// (and some of sub-sequence 4 below)
+	if isBaseCellPentagon(baseCell) && leadingNonZeroDigit(h) == ikAxesDigit {
+		h = rotate60cw(h)
+	}
+
+	// start with the "home" face and ijk+ coordinates for the base cell
+	fijk := baseCellData[baseCell].homeFijk
+	if !h3ToFaceIjkWithInitializedFijk(h, &fijk) {
+		// no overage is possible; h lies on this face
+		return fijk
+	}
+
+	// if we're here we have the potential for an "overage"; i.e., it is possible that the
+	// cell lies on an adjacent face
+	origIJK := fijk.coord
+
+	// if we're in Class III, drop into the next finer Class II grid
+	res := getResolution(h)
+	if isResClassIII(res) {
+		fijk.coord.downAp7r()
+		res++
+	}
+
+	// adjust for overage if needed; a pentagon base cell with a leading 4 digit requires
+	// special handling
+	pentLeading4 := isBaseCellPentagon(baseCell) && leadingNonZeroDigit(h) == iAxesDigit
+	if adjustOverageClassII(&fijk, res, pentLeading4, false) != noOverage {
+		// if the base cell is a pentagon we have the potential for secondary overages
+		if isBaseCellPentagon(baseCell) {
+			for adjustOverageClassII(&fijk, res, false, false) != noOverage {
+			}
+		}
+		if res != getResolution(h) {
+			fijk.coord.upAp7r()
+		}
+	} else if res != getResolution(h) {
+		fijk.coord = origIJK
+	}
+	return fijk
+}
+
+// cellToParent returns the parent of a cell at a coarser resolution, or InvalidH3Index if
+// parentRes is out of range or finer than the cell.
+func cellToParent(h h3Index, parentRes int) h3Index {
+	childRes := getResolution(h)
+	if parentRes < 0 || parentRes > maxH3Res || parentRes > childRes {
+		return InvalidH3Index
+	}
+	setResolution(&h, parentRes)
+	for r := parentRes + 1; r <= childRes; r++ {
+		setIndexDigit(&h, r, invalidDigit)
+	}
+	return h
+}
+
+// cellToChildrenSize returns the number of children of a cell at a resolution, or 0 if childRes
+// is out of range or coarser than the cell.
+func cellToChildrenSize(h h3Index, childRes int) int64 {
+	res := getResolution(h)
+	if childRes < res || childRes > maxH3Res {
+		return 0
+	}
+	n := int64(1)
+	for i := res; i < childRes; i++ {
+		n *= 7
+	}
+	if isPentagon(h) {
+		return 1 + 5*(n-1)/6
+	}
+	return n
+}
+
+// zeroIndexDigits zeroes the digits of an index from resolution start to end, inclusive.
+func zeroIndexDigits(h h3Index, start, end int) h3Index {
+	if start > end {
+		return h
+	}
+	m := ^uint64(0)
+	m <<= perDigitOffset * uint(end-start+1)
+	m = ^m
+	m <<= perDigitOffset * uint(maxH3Res-end)
+	m = ^m
+	return h3Index(uint64(h) & m)
+}
+
+func setReservedBits(h *h3Index, v int) {
+	*h = h3Index(uint64(*h)&^reservedMask | uint64(v)<<reservedOffset)
+}
+
+// compactCells merges full sets of children into their parents, recursively. The cells must
+// share a resolution and have no duplicates; it returns false if it finds that they don't.
+func compactCells(h3Set []h3Index) ([]h3Index, bool) {
+	numHexes := len(h3Set)
+	if numHexes == 0 {
+		return nil, true
+	}
+	res := getResolution(h3Set[0])
+	if res == 0 {
+		// no compaction possible
+		return append([]h3Index(nil), h3Set...), true
+	}
+
+	remainingHexes := append([]h3Index(nil), h3Set...)
+	hashSetArray := make([]h3Index, numHexes)
+	compactedSet := make([]h3Index, 0, numHexes)
+	numRemainingHexes := numHexes
+	for numRemainingHexes > 0 {
+		res = getResolution(remainingHexes[0])
+		parentRes := res - 1
+
+		// put the parents of the cells into the hash set, using the reserved bits to count
+		// how many times a parent is seen
+		if parentRes >= 0 {
+			for i := 0; i < numRemainingHexes; i++ {
+				currIndex := remainingHexes[i]
+				if currIndex == InvalidH3Index {
+					continue
+				}
+				if getReservedBits(currIndex) != 0 {
+					return nil, false
+				}
+				parent := cellToParent(currIndex, parentRes)
+				if parent == InvalidH3Index {
+					return nil, false
+				}
+				loc := int(uint64(parent) % uint64(numRemainingHexes))
+				loopCount := 0
+				for hashSetArray[loc] != InvalidH3Index {
+					if loopCount > numRemainingHexes {
+						return nil, false
+					}
+					tempIndex := h3Index(uint64(hashSetArray[loc]) &^ reservedMask)
+					if tempIndex == parent {
+						count := getReservedBits(hashSetArray[loc]) + 1
+						limitCount := 7
+						if isPentagon(tempIndex) {
+							limitCount--
+						}
+						if count+1 > limitCount {
+							// only possible with duplicate input
+							return nil, false
+						}
+						setReservedBits(&parent, count)
+						hashSetArray[loc] = InvalidH3Index
+					} else {
+						loc = (loc + 1) % numRemainingHexes
+					}
+					loopCount++
+				}
+				hashSetArray[loc] = parent
+			}
+		}
+
+		// find the parents with a complete set of children
+		maxCompactableCount := numRemainingHexes / 6
+		if maxCompactableCount == 0 {
+			compactedSet = append(compactedSet, remainingHexes[:numRemainingHexes]...)
+			break
+		}
+		compactableHexes := make([]h3Index, 0, maxCompactableCount)
+		for i := 0; i < numRemainingHexes; i++ {
+			if hashSetArray[i] == InvalidH3Index {
+				continue
+			}
+			count := getReservedBits(hashSetArray[i]) + 1
+			// the deleted direction of a pentagon is implicitly there
+			if isPentagon(h3Index(uint64(hashSetArray[i]) &^ reservedMask)) {
+				setReservedBits(&hashSetArray[i], count)
+				count++
+			}
+			if count == 7 {
+				compactableHexes = append(compactableHexes, h3Index(uint64(hashSetArray[i])&^reservedMask))
+			}
+		}
+
+		// cells whose parents are incomplete are copied to the output
+		for i := 0; i < numRemainingHexes; i++ {
+			currIndex := remainingHexes[i]
+			if currIndex == InvalidH3Index {
+				continue
+			}
+			parent := cellToParent(currIndex, parentRes)
+			if parent == InvalidH3Index {
+				return nil, false
+			}
+			loc := int(uint64(parent) % uint64(numRemainingHexes))
+			loopCount := 0
+			isUncompactable := true
+			for {
+				if loopCount > numRemainingHexes {
+					return nil, false
+				}
+				tempIndex := h3Index(uint64(hashSetArray[loc]) &^ reservedMask)
+				if tempIndex == parent {
+					if getReservedBits(hashSetArray[loc])+1 == 7 {
+						isUncompactable = false
+					}
+					break
+				}
+				loc = (loc + 1) % numRemainingHexes
+				loopCount++
+				if hashSetArray[loc] == parent {
+					break
+				}
+			}
+			if isUncompactable {
+				compactedSet = append(compactedSet, currIndex)
+			}
+		}
+
+		// set up for the next loop
+		for i := range hashSetArray {
+			hashSetArray[i] = InvalidH3Index
+		}
+		copy(remainingHexes, compactableHexes)
+		numRemainingHexes = len(compactableHexes)
+	}
+	return compactedSet, true
+}
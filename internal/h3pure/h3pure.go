@@ -0,0 +1,293 @@
+// Package h3pure is a pure Go port of the parts of the H3 v4.1.0 C library used by placekey-go.
+//
+// Its API mirrors the subset of github.com/uber/h3-go/v4 that placekey-go calls, so that builds
+// without cgo can use it in place of the C bindings. The port follows the C sources closely,
+// including the order in which GridDisk and PolygonToCells return cells.
+package h3pure
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Cell is a 64-bit H3 cell index.
+type Cell int64
+
+// InvalidH3Index is the index returned for invalid input.
+const InvalidH3Index = 0
+
+// LatLng is a (latitude, longitude) in degrees.
+type LatLng struct {
+	Lat, Lng float64
+}
+
+// NewLatLng returns a LatLng from a latitude and longitude in degrees.
+func NewLatLng(lat, lng float64) LatLng {
+	return LatLng{Lat: lat, Lng: lng}
+}
+
+// CellBoundary is the vertices of a cell in counter-clockwise order.
+type CellBoundary []LatLng
+
+// GeoLoop is a closed ring of points; the first point is not repeated at the end.
+type GeoLoop []LatLng
+
+// GeoPolygon is a polygon with an exterior ring and any number of holes.
+type GeoPolygon struct {
+	GeoLoop GeoLoop
+	Holes   []GeoLoop
+}
+
+// LatLngToCell returns the cell containing a (latitude, longitude) at a resolution.
+func LatLngToCell(latLng LatLng, resolution int) Cell {
+	if resolution < 0 || resolution > maxH3Res {
+		return InvalidH3Index
+	}
+	g := toRadians(latLng)
+	if math.IsNaN(g.lat) || math.IsInf(g.lat, 0) || math.IsNaN(g.lon) || math.IsInf(g.lon, 0) {
+		return InvalidH3Index
+	}
+	return Cell(faceIjkToH3(geoToFaceIjk(g, resolution), resolution))
+}
+
+// CellToLatLng returns the center of a cell.
+func CellToLatLng(c Cell) LatLng {
+	h := h3Index(c)
+	return toDegrees(faceIjkToGeo(h3ToFaceIjk(h), getResolution(h)))
+}
+
+// CellToBoundary returns the vertices of a cell.
+func CellToBoundary(c Cell) CellBoundary {
+	h := h3Index(c)
+	verts := faceIjkToGeoBoundary(h3ToFaceIjk(h), getResolution(h), isPentagon(h))
+	cb := make(CellBoundary, 0, maxCellBndryVerts)
+	for _, v := range verts {
+		cb = append(cb, toDegrees(v))
+	}
+	return cb
+}
+
+// Resolution returns the resolution of a cell.
+func (c Cell) Resolution() int {
+	return getResolution(h3Index(c))
+}
+
+// BaseCellNumber returns the base cell number of a cell.
+func (c Cell) BaseCellNumber() int {
+	return getBaseCell(h3Index(c))
+}
+
+// IsValid returns whether an index is a valid cell.
+func (c Cell) IsValid() bool {
+	h := h3Index(c)
+	if h == InvalidH3Index || getHighBit(h) != 0 || getMode(h) != hexagonMode || getReservedBits(h) != 0 {
+		return false
+	}
+
+	baseCell := getBaseCell(h)
+	if baseCell < 0 || baseCell >= numBaseCells {
+		return false
+	}
+
+	res := getResolution(h)
+	if res < 0 || res > maxH3Res {
+		return false
+	}
+
+	foundFirstNonZeroDigit := false
+	for r := 1; r <= res; r++ {
+		digit := getIndexDigit(h, r)
+		if !foundFirstNonZeroDigit && digit != centerDigit {
+			foundFirstNonZeroDigit = true
+			if isBaseCellPentagon(baseCell) && digit == kAxesDigit {
+				return false
+			}
+		}
+		if digit < centerDigit || digit >= numDigits {
+			return false
+		}
+	}
+
+	for r := res + 1; r <= maxH3Res; r++ {
+		if getIndexDigit(h, r) != invalidDigit {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsPentagon returns whether a cell is a pentagon.
+func (c Cell) IsPentagon() bool {
+	return isPentagon(h3Index(c))
+}
+
+// String returns the hexadecimal string of a cell.
+func (c Cell) String() string {
+	return IndexToString(uint64(c))
+}
+
+// Parent returns the parent of the cell at a coarser resolution, or InvalidH3Index if the
+// resolution is out of range or finer than the cell's.
+func (c Cell) Parent(resolution int) Cell {
+	return Cell(cellToParent(h3Index(c), resolution))
+}
+
+// Children returns the children of the cell at a finer resolution, or nil if the resolution
+// is out of range or coarser than the cell's.
+func (c Cell) Children(resolution int) []Cell {
+	n := cellToChildrenSize(h3Index(c), resolution)
+	if n == 0 {
+		return nil
+	}
+	out := make([]Cell, 0, n)
+	for it := iterInitParent(h3Index(c), resolution); it.h != InvalidH3Index; it.step() {
+		out = append(out, Cell(it.h))
+	}
+	return out
+}
+
+// CompactCells merges full sets of children into their parents, recursively, until no more
+// merges are possible. The cells must share a resolution and have no duplicates. It returns nil
+// if it finds that they don't, though like the C library it doesn't catch every duplicate.
+func CompactCells(in []Cell) []Cell {
+	h3Set := make([]h3Index, len(in))
+	for i, c := range in {
+		h3Set[i] = h3Index(c)
+	}
+	out, ok := compactCells(h3Set)
+	if !ok {
+		return nil
+	}
+	return compactZeros(out)
+}
+
+// UncompactCells returns the children at a resolution of every cell, skipping zeros. It returns
+// nil if a cell is finer than the resolution.
+func UncompactCells(in []Cell, resolution int) []Cell {
+	n := int64(0)
+	for _, c := range in {
+		if c == InvalidH3Index {
+			continue
+		}
+		size := cellToChildrenSize(h3Index(c), resolution)
+		if size == 0 {
+			return nil
+		}
+		n += size
+	}
+	out := make([]Cell, 0, n)
+	for _, c := range in {
+		for it := iterInitParent(h3Index(c), resolution); it.h != InvalidH3Index; it.step() {
+			out = append(out, Cell(it.h))
+		}
+	}
+	return out
+}
+
+// GridDistance returns the number of steps between two cells, or -1 if it can't be found
+// because they are too far apart or on opposite sides of a pentagon. The C bindings return 0
+// instead.
+func GridDistance(a, b Cell) int {
+	d, ok := gridDistance(h3Index(a), h3Index(b))
+	if !ok {
+		return -1
+	}
+	return d
+}
+
+// GridPath returns the line of cells from a to b, inclusive, each a neighbor of the one before.
+// It returns nil if the line can't be found because the cells are too far apart or the line
+// crosses pentagon distortion.
+func GridPath(a, b Cell) []Cell {
+	line, ok := gridPathCells(h3Index(a), h3Index(b))
+	if !ok {
+		return nil
+	}
+	out := make([]Cell, len(line))
+	for i, h := range line {
+		out[i] = Cell(h)
+	}
+	return out
+}
+
+// IndexFromString returns the index of a hexadecimal string with an optional 0x prefix, or 0.
+func IndexFromString(s string) uint64 {
+	if len(s) > 2 && strings.ToLower(s[:2]) == "0x" {
+		s = s[2:]
+	}
+	i, _ := strconv.ParseUint(s, 16, 64)
+	return i
+}
+
+// IndexToString returns the hexadecimal string of an index.
+func IndexToString(i uint64) string {
+	return strconv.FormatUint(i, 16)
+}
+
+// GridDisk returns the cells within k steps of origin, including origin.
+func GridDisk(origin Cell, k int) []Cell {
+	out := make([]h3Index, maxKringSize(k))
+	kRing(h3Index(origin), k, out)
+	return compactZeros(out)
+}
+
+// GridDiskDistances returns the cells within k steps of origin, grouped by their distance from
+// it. Unlike the C bindings, it leaves out the zeros that occur around pentagons.
+func GridDiskDistances(origin Cell, k int) [][]Cell {
+	out := make([]h3Index, maxKringSize(k))
+	distances := make([]int, len(out))
+	kRingDistances(h3Index(origin), k, out, distances)
+
+	rings := make([][]Cell, k+1)
+	for i, h := range out {
+		if h != InvalidH3Index {
+			rings[distances[i]] = append(rings[distances[i]], Cell(h))
+		}
+	}
+	return rings
+}
+
+// PolygonToCells returns the cells at a resolution whose centers are inside a polygon.
+func PolygonToCells(polygon GeoPolygon, resolution int) []Cell {
+	if len(polygon.GeoLoop) == 0 {
+		return nil
+	}
+	geofence := toRadiansLoop(polygon.GeoLoop)
+	holes := make([][]geoCoord, len(polygon.Holes))
+	for i, hole := range polygon.Holes {
+		holes[i] = toRadiansLoop(hole)
+	}
+	return compactZeros(polyfill(geofence, holes, resolution))
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+func toRadians(g LatLng) geoCoord {
+	return geoCoord{lat: deg2rad * g.Lat, lon: deg2rad * g.Lng}
+}
+
+func toDegrees(g geoCoord) LatLng {
+	return LatLng{Lat: rad2deg * g.lat, Lng: rad2deg * g.lon}
+}
+
+func toRadiansLoop(loop []LatLng) []geoCoord {
+	out := make([]geoCoord, len(loop))
+	for i, g := range loop {
+		out[i] = toRadians(g)
+	}
+	return out
+}
+
+// compactZeros removes the zeros that the C library leaves in sparse output arrays.
+func compactZeros(hs []h3Index) []Cell {
+	out := make([]Cell, 0, len(hs))
+	for _, h := range hs {
+		if h != InvalidH3Index {
+			out = append(out, Cell(h))
+		}
+	}
+	return out
+}
@@ -0,0 +1,418 @@
+//go:build cgo
+// +build cgo
+
+package h3pure
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/uber/h3-go/v4"
+)
+
+// The tests below check the port against the C library through uber/h3-go/v4, so they only run
+// in builds with cgo.
+
+func randomLatLngs(n int) []LatLng {
+	r := rand.New(rand.NewSource(13))
+	coords := []LatLng{
+		{Lat: 90, Lng: 0},
+		{Lat: -90, Lng: 0},
+		{Lat: 0, Lng: 180},
+		{Lat: 0, Lng: -180},
+		{Lat: 37.23712, Lng: -115.80187},
+	}
+	for bc := 0; bc < numBaseCells; bc++ {
+		coords = append(coords, LatLng(h3.CellToLatLng(h3.Cell(0x8001fffffffffff|int64(bc)<<45))))
+	}
+	for len(coords) < n {
+		coords = append(coords, LatLng{Lat: r.Float64()*180 - 90, Lng: r.Float64()*360 - 180})
+	}
+	return coords
+}
+
+// pentagons returns every pentagon at a resolution.
+func pentagons(res int) []h3.Cell {
+	cells := []h3.Cell{}
+	for bc := 0; bc < numBaseCells; bc++ {
+		if !baseCellData[bc].isPentagon {
+			continue
+		}
+		h := h3Index(h3Init)
+		setMode(&h, hexagonMode)
+		setBaseCell(&h, bc)
+		setResolution(&h, res)
+		for r := 1; r <= res; r++ {
+			setIndexDigit(&h, r, centerDigit)
+		}
+		cells = append(cells, h3.Cell(h))
+	}
+	return cells
+}
+
+// closeTo returns whether two points are within about a millimeter of each other. Longitudes
+// are scaled by the cosine of the latitude, since they are ill-conditioned near the poles.
+func closeTo(a, b LatLng) bool {
+	cos := math.Cos(a.Lat * math.Pi / 180)
+	return math.Abs(a.Lat-b.Lat) < 1e-8 && math.Abs(a.Lng-b.Lng)*cos < 1e-8
+}
+
+func TestLatLngToCell(t *testing.T) {
+	for res := 0; res <= maxH3Res; res++ {
+		n := 2000
+		if res == 10 {
+			n = 50000
+		}
+		for _, g := range randomLatLngs(n) {
+			want := h3.LatLngToCell(h3.LatLng(g), res)
+			if got := LatLngToCell(g, res); uint64(got) != uint64(want) {
+				t.Fatalf(`LatLngToCell(%v, %d) = %x; wanted %x`, g, res, uint64(got), uint64(want))
+			}
+		}
+	}
+	for _, g := range []LatLng{{math.NaN(), 0}, {0, math.Inf(1)}} {
+		if got := LatLngToCell(g, 10); got != InvalidH3Index {
+			t.Errorf(`LatLngToCell(%v, 10) = %x; wanted 0`, g, uint64(got))
+		}
+	}
+	if got := LatLngToCell(LatLng{}, 16); got != InvalidH3Index {
+		t.Errorf(`LatLngToCell({0, 0}, 16) = %x; wanted 0`, uint64(got))
+	}
+}
+
+func TestCellToLatLngAndBoundary(t *testing.T) {
+	cells := []h3.Cell{}
+	for res := 0; res <= maxH3Res; res++ {
+		cells = append(cells, pentagons(res)...)
+		for _, g := range randomLatLngs(2000) {
+			cells = append(cells, h3.LatLngToCell(h3.LatLng(g), res))
+		}
+	}
+
+	for _, c := range cells {
+		want := LatLng(h3.CellToLatLng(c))
+		got := CellToLatLng(Cell(c))
+		if !closeTo(got, want) {
+			t.Fatalf(`CellToLatLng(%x) = %v; wanted %v`, uint64(c), got, want)
+		}
+
+		wantBoundary := h3.CellToBoundary(c)
+		gotBoundary := CellToBoundary(Cell(c))
+		if len(gotBoundary) != len(wantBoundary) {
+			t.Fatalf(`CellToBoundary(%x) has %d vertices; wanted %d`, uint64(c), len(gotBoundary), len(wantBoundary))
+		}
+		for i := range gotBoundary {
+			if !closeTo(gotBoundary[i], LatLng(wantBoundary[i])) {
+				t.Fatalf(`CellToBoundary(%x)[%d] = %v; wanted %v`, uint64(c), i, gotBoundary[i], wantBoundary[i])
+			}
+		}
+
+		if got, want := Cell(c).IsPentagon(), c.IsPentagon(); got != want {
+			t.Fatalf(`Cell(%x).IsPentagon() = %v; wanted %v`, uint64(c), got, want)
+		}
+		if got, want := Cell(c).BaseCellNumber(), c.BaseCellNumber(); got != want {
+			t.Fatalf(`Cell(%x).BaseCellNumber() = %d; wanted %d`, uint64(c), got, want)
+		}
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	r := rand.New(rand.NewSource(14))
+	cells := []uint64{0, math.MaxUint64}
+	for _, g := range randomLatLngs(1000) {
+		h := uint64(h3.LatLngToCell(h3.LatLng(g), 10))
+		cells = append(cells, h)
+		// flip single bits of valid cells
+		for i := 0; i < 8; i++ {
+			cells = append(cells, h^1<<uint(r.Intn(64)))
+		}
+	}
+	for _, p := range pentagons(10) {
+		// a leading k-axes digit is deleted in pentagons
+		cells = append(cells, uint64(p), uint64(p)|1<<(3*(maxH3Res-1)), uint64(p)|1<<(3*(maxH3Res-10)))
+	}
+	for i := 0; i < 10000; i++ {
+		cells = append(cells, r.Uint64())
+	}
+
+	for _, c := range cells {
+		if got, want := Cell(c).IsValid(), h3.Cell(c).IsValid(); got != want {
+			t.Errorf(`Cell(%x).IsValid() = %v; wanted %v`, c, got, want)
+		}
+	}
+}
+
+func TestGridDisk(t *testing.T) {
+	origins := []h3.Cell{}
+	for _, res := range []int{0, 1, 5, 10} {
+		origins = append(origins, pentagons(res)...)
+		for _, g := range randomLatLngs(300) {
+			origins = append(origins, h3.LatLngToCell(h3.LatLng(g), res))
+		}
+	}
+	for _, o := range origins {
+		for _, k := range []int{0, 1, 2, 5} {
+			want := h3.GridDisk(o, k)
+			got := GridDisk(Cell(o), k)
+			if len(got) != len(want) {
+				t.Fatalf(`GridDisk(%x, %d) has %d cells; wanted %d`, uint64(o), k, len(got), len(want))
+			}
+			for i := range got {
+				if uint64(got[i]) != uint64(want[i]) {
+					t.Fatalf(`GridDisk(%x, %d)[%d] = %x; wanted %x`, uint64(o), k, i, uint64(got[i]), uint64(want[i]))
+				}
+			}
+
+			wantRings := h3.GridDiskDistances(o, k)
+			gotRings := GridDiskDistances(Cell(o), k)
+			if len(gotRings) != len(wantRings) {
+				t.Fatalf(`GridDiskDistances(%x, %d) has %d rings; wanted %d`, uint64(o), k, len(gotRings), len(wantRings))
+			}
+			for d := range gotRings {
+				// the C bindings keep the zeros left around pentagons
+				ring := []h3.Cell{}
+				for _, c := range wantRings[d] {
+					if c != 0 {
+						ring = append(ring, c)
+					}
+				}
+				if len(gotRings[d]) != len(ring) {
+					t.Fatalf(`GridDiskDistances(%x, %d)[%d] has %d cells; wanted %d`, uint64(o), k, d, len(gotRings[d]), len(ring))
+				}
+				for i := range ring {
+					if uint64(gotRings[d][i]) != uint64(ring[i]) {
+						t.Fatalf(`GridDiskDistances(%x, %d)[%d][%d] = %x; wanted %x`, uint64(o), k, d, i, uint64(gotRings[d][i]), uint64(ring[i]))
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestParentChildren(t *testing.T) {
+	cells := []h3.Cell{}
+	for _, res := range []int{0, 1, 2, 5} {
+		cells = append(cells, pentagons(res)...)
+		for _, g := range randomLatLngs(200) {
+			cells = append(cells, h3.LatLngToCell(h3.LatLng(g), res))
+		}
+	}
+	for _, c := range cells {
+		for _, res := range []int{0, c.Resolution(), c.Resolution() + 1, c.Resolution() + 3} {
+			if got, want := Cell(c).Parent(res), c.Parent(res); uint64(got) != uint64(want) {
+				t.Fatalf(`Cell(%x).Parent(%d) = %x; wanted %x`, uint64(c), res, uint64(got), uint64(want))
+			}
+			if res < c.Resolution() {
+				continue
+			}
+			want := c.Children(res)
+			got := Cell(c).Children(res)
+			if len(got) != len(want) {
+				t.Fatalf(`Cell(%x).Children(%d) has %d cells; wanted %d`, uint64(c), res, len(got), len(want))
+			}
+			for i := range got {
+				if uint64(got[i]) != uint64(want[i]) {
+					t.Fatalf(`Cell(%x).Children(%d)[%d] = %x; wanted %x`, uint64(c), res, i, uint64(got[i]), uint64(want[i]))
+				}
+				if p := got[i].Parent(c.Resolution()); uint64(p) != uint64(c) {
+					t.Fatalf(`Cell(%x).Parent(%d) = %x; wanted %x`, uint64(got[i]), c.Resolution(), uint64(p), uint64(c))
+				}
+			}
+		}
+	}
+	if got := Cell(0x8a2a1072b59ffff).Children(9); got != nil {
+		t.Errorf(`Cell(8a2a1072b59ffff).Children(9) = %v; wanted nil`, got)
+	}
+	if got := Cell(0x8a2a1072b59ffff).Parent(16); got != InvalidH3Index {
+		t.Errorf(`Cell(8a2a1072b59ffff).Parent(16) = %x; wanted 0`, uint64(got))
+	}
+}
+
+func TestCompactCells(t *testing.T) {
+	r := rand.New(rand.NewSource(16))
+	sets := [][]h3.Cell{}
+	for _, p := range pentagons(2) {
+		sets = append(sets, p.Children(5))
+	}
+	for _, g := range randomLatLngs(150) {
+		c := h3.LatLngToCell(h3.LatLng(g), 6)
+		disk := h3.GridDisk(c, 2)
+		set := []h3.Cell{}
+		for _, d := range disk {
+			set = append(set, d.Children(8)...)
+		}
+		// drop some cells and shuffle the rest
+		r.Shuffle(len(set), func(i, j int) { set[i], set[j] = set[j], set[i] })
+		sets = append(sets, set[:len(set)-r.Intn(len(set)/4)])
+	}
+
+	for _, set := range sets {
+		in := make([]Cell, len(set))
+		for i, c := range set {
+			in[i] = Cell(c)
+		}
+		want := h3.CompactCells(set)
+		got := CompactCells(in)
+		if len(got) != len(want) {
+			t.Fatalf(`CompactCells(%x...) has %d cells; wanted %d`, uint64(set[0]), len(got), len(want))
+		}
+		for i := range got {
+			if uint64(got[i]) != uint64(want[i]) {
+				t.Fatalf(`CompactCells(%x...)[%d] = %x; wanted %x`, uint64(set[0]), i, uint64(got[i]), uint64(want[i]))
+			}
+		}
+
+		res := set[0].Resolution()
+		wantCells := h3.UncompactCells(want, res)
+		gotCells := UncompactCells(got, res)
+		if len(gotCells) != len(wantCells) || len(gotCells) != len(set) {
+			t.Fatalf(`UncompactCells(%x..., %d) has %d cells; wanted %d`, uint64(want[0]), res, len(gotCells), len(wantCells))
+		}
+		for i := range gotCells {
+			if uint64(gotCells[i]) != uint64(wantCells[i]) {
+				t.Fatalf(`UncompactCells(%x..., %d)[%d] = %x; wanted %x`, uint64(want[0]), res, i, uint64(gotCells[i]), uint64(wantCells[i]))
+			}
+		}
+	}
+
+	mixed := []Cell{0x8a2a1072b59ffff, 0x852a1073fffffff}
+	if got := CompactCells(mixed); got != nil {
+		t.Errorf(`CompactCells(%v) = %v; wanted nil`, mixed, got)
+	}
+	if got := UncompactCells([]Cell{0x8a2a1072b59ffff}, 9); got != nil {
+		t.Errorf(`UncompactCells([8a2a1072b59ffff], 9) = %v; wanted nil`, got)
+	}
+}
+
+func TestGridPathAndDistance(t *testing.T) {
+	r := rand.New(rand.NewSource(17))
+	pairs := [][2]h3.Cell{}
+	for _, res := range []int{0, 1, 5, 10} {
+		starts := pentagons(res)
+		for _, g := range randomLatLngs(300) {
+			starts = append(starts, h3.LatLngToCell(h3.LatLng(g), res))
+		}
+		for _, start := range starts {
+			// ends within a few steps, around pentagons and across base cells
+			disk := h3.GridDisk(start, 1+r.Intn(12))
+			pairs = append(pairs, [2]h3.Cell{start, disk[r.Intn(len(disk))]})
+			pairs = append(pairs, [2]h3.Cell{start, disk[len(disk)-1]})
+		}
+	}
+
+	failed := 0
+	for _, p := range pairs {
+		got := GridPath(Cell(p[0]), Cell(p[1]))
+
+		// the bindings index into an empty slice when the C library can't find the distance,
+		// and leave zeros in the line when it fails part way
+		var want []h3.Cell
+		if p[0] == p[1] || h3.GridDistance(p[0], p[1]) != 0 {
+			want = h3.GridPath(p[0], p[1])
+			for _, c := range want {
+				if c == 0 {
+					want = nil
+					break
+				}
+			}
+		}
+		if (got == nil) != (want == nil) {
+			t.Fatalf(`GridPath(%x, %x) = %x; wanted %x`, uint64(p[0]), uint64(p[1]), got, want)
+		}
+
+		wantDistance := h3.GridDistance(p[0], p[1])
+		if p[0] != p[1] && wantDistance == 0 {
+			wantDistance = -1
+		}
+		if got := GridDistance(Cell(p[0]), Cell(p[1])); got != wantDistance {
+			t.Fatalf(`GridDistance(%x, %x) = %d; wanted %d`, uint64(p[0]), uint64(p[1]), got, wantDistance)
+		}
+		if want == nil {
+			failed++
+			continue
+		}
+		if len(got) != len(want) {
+			t.Fatalf(`GridPath(%x, %x) has %d cells; wanted %d`, uint64(p[0]), uint64(p[1]), len(got), len(want))
+		}
+		for i := range got {
+			if uint64(got[i]) != uint64(want[i]) {
+				t.Fatalf(`GridPath(%x, %x)[%d] = %x; wanted %x`, uint64(p[0]), uint64(p[1]), i, uint64(got[i]), uint64(want[i]))
+			}
+		}
+	}
+	if failed > len(pairs)/2 {
+		t.Errorf(`GridPath failed for %d of %d pairs`, failed, len(pairs))
+	}
+}
+
+func TestPolygonToCells(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	polygons := []GeoPolygon{
+		{},
+		{
+			GeoLoop: GeoLoop{{37.813318999983238, -122.4089866999972145}, {37.7866302000007224, -122.3805436999997056}, {37.7198061999978478, -122.3544736999993603}, {37.7076131999975672, -122.5123436999983966}, {37.7835871999971715, -122.5247187000021967}, {37.8151571999998453, -122.4798767000009008}},
+			Holes:   []GeoLoop{{{37.7869802, -122.4471197}, {37.7664102, -122.4590777}, {37.7710682, -122.4137097}}},
+		},
+		// crosses the antimeridian
+		{GeoLoop: GeoLoop{{0.05, 179.95}, {0.05, -179.95}, {-0.05, -179.95}, {-0.05, 179.95}}},
+	}
+
+	// around each pentagon
+	for _, p := range pentagons(0) {
+		c := h3.CellToLatLng(p)
+		polygons = append(polygons, GeoPolygon{GeoLoop: GeoLoop{
+			{c.Lat - 0.02, c.Lng - 0.02}, {c.Lat - 0.02, c.Lng + 0.02},
+			{c.Lat + 0.02, c.Lng + 0.02}, {c.Lat + 0.02, c.Lng - 0.02},
+		}})
+	}
+	for i := 0; i < 20; i++ {
+		lat, lng := r.Float64()*160-80, r.Float64()*340-170
+		d := r.Float64() * 0.05
+		polygons = append(polygons, GeoPolygon{GeoLoop: GeoLoop{
+			{lat - d, lng - d}, {lat - d, lng + d}, {lat + d, lng + 2*d}, {lat + d, lng - d},
+		}})
+	}
+
+	for _, p := range polygons {
+		cp := h3.GeoPolygon{}
+		for _, g := range p.GeoLoop {
+			cp.GeoLoop = append(cp.GeoLoop, h3.LatLng(g))
+		}
+		for _, hole := range p.Holes {
+			ch := h3.GeoLoop{}
+			for _, g := range hole {
+				ch = append(ch, h3.LatLng(g))
+			}
+			cp.Holes = append(cp.Holes, ch)
+		}
+
+		for _, res := range []int{5, 9, 10} {
+			want := h3.PolygonToCells(cp, res)
+			got := PolygonToCells(p, res)
+			if len(got) != len(want) {
+				t.Fatalf(`PolygonToCells(%v, %d) has %d cells; wanted %d`, p, res, len(got), len(want))
+			}
+			for i := range got {
+				if uint64(got[i]) != uint64(want[i]) {
+					t.Fatalf(`PolygonToCells(%v, %d)[%d] = %x; wanted %x`, p, res, i, uint64(got[i]), uint64(want[i]))
+				}
+			}
+		}
+	}
+}
+
+func TestStrings(t *testing.T) {
+	for _, s := range []string{"8a2a1072b59ffff", "0x8a2a1072b59ffff", "0X8A2A1072B59FFFF", "0", "0x", "", "zz", "ffffffffffffffffff"} {
+		i := IndexFromString(s)
+		if want := h3.IndexFromString(s); i != want {
+			t.Errorf(`IndexFromString("%s") = %x; wanted %x`, s, i, want)
+		}
+		if got, want := IndexToString(i), h3.IndexToString(i); got != want {
+			t.Errorf(`IndexToString(%x) = "%s"; wanted "%s"`, i, got, want)
+		}
+		if got, want := Cell(i).String(), h3.Cell(i).String(); got != want {
+			t.Errorf(`Cell(%x).String() = "%s"; wanted "%s"`, i, got, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package h3pure
+
+// pentagonSkippedDigit is the digit that never leads the children of a pentagon.
+const pentagonSkippedDigit = kAxesDigit
+
+// iterCellsChildren iterates over the children of a cell at a resolution, in order. h is the
+// current child, or InvalidH3Index once the iteration is over.
+type iterCellsChildren struct {
+	h         h3Index
+	parentRes int
+	// skipDigit is the resolution whose digit skips pentagonSkippedDigit, or -1 if the parent
+	// is not a pentagon. It moves to coarser resolutions as the iteration counts up.
+	skipDigit int
+}
+
+func nullIter() iterCellsChildren {
+	return iterCellsChildren{h: InvalidH3Index, parentRes: -1, skipDigit: -1}
+}
+
+// iterInitParent starts an iteration over the children of h at childRes.
+func iterInitParent(h h3Index, childRes int) iterCellsChildren {
+	it := iterCellsChildren{parentRes: getResolution(h)}
+	if childRes < it.parentRes || childRes > maxH3Res || h == InvalidH3Index {
+		return nullIter()
+	}
+
+	it.h = zeroIndexDigits(h, it.parentRes+1, childRes)
+	setResolution(&it.h, childRes)
+
+	if isPentagon(it.h) {
+		it.skipDigit = childRes
+	} else {
+		it.skipDigit = -1
+	}
+	return it
+}
+
+// step moves the iteration to the next child.
+func (it *iterCellsChildren) step() {
+	if it.h == InvalidH3Index {
+		return
+	}
+
+	childRes := getResolution(it.h)
+	it.incrementResDigit(childRes)
+
+	for r := childRes; r >= it.parentRes; r-- {
+		if r == it.parentRes {
+			// the parent resolution digit would change, so we're done
+			*it = nullIter()
+			return
+		}
+
+		// the first nonzero digit of the children of a pentagon is never 1
+		if r == it.skipDigit && getIndexDigit(it.h, r) == pentagonSkippedDigit {
+			it.incrementResDigit(r)
+			it.skipDigit--
+			return
+		}
+
+		if getIndexDigit(it.h, r) == invalidDigit {
+			// zeroes the digit and carries into the next coarser one
+			it.incrementResDigit(r)
+		} else {
+			break
+		}
+	}
+}
+
+func (it *iterCellsChildren) incrementResDigit(res int) {
+	it.h += h3Index(1) << (perDigitOffset * uint(maxH3Res-res))
+}
@@ -0,0 +1,457 @@
+package h3pure
+
+import "math"
+
+// pentagonRotations maps the leading digit of an origin and the leading digit of an index to
+// 60 degree cw rotations. Either being 1 (the k axes) is invalid.
+var pentagonRotations = [7][7]int{
+	{0, -1, 0, 0, 0, 0, 0},
+	{-1, -1, -1, -1, -1, -1, -1},
+	{0, -1, 0, 0, 0, 1, 0},
+	{0, -1, 0, 0, 1, 1, 0},
+	{0, -1, 0, 5, 0, 0, 0},
+	{0, -1, 5, 5, 0, 0, 0},
+	{0, -1, 0, 0, 0, 0, 0},
+}
+
+// pentagonRotationsReverse maps a reverse base cell direction and a leading index digit to 60
+// degree ccw rotations, reversing pentagonRotations when the origin is on a pentagon.
+var pentagonRotationsReverse = [7][7]int{
+	{0, 0, 0, 0, 0, 0, 0},
+	{-1, -1, -1, -1, -1, -1, -1},
+	{0, 1, 0, 0, 0, 0, 0},
+	{0, 1, 0, 0, 0, 1, 0},
+	{0, 5, 0, 0, 0, 0, 0},
+	{0, 5, 0, 5, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0},
+}
+
+// pentagonRotationsReverseNonpolar is pentagonRotationsReverse for an index on a non-polar
+// pentagon when the origin is not on a pentagon.
+var pentagonRotationsReverseNonpolar = [7][7]int{
+	{0, 0, 0, 0, 0, 0, 0},
+	{-1, -1, -1, -1, -1, -1, -1},
+	{0, 1, 0, 0, 0, 0, 0},
+	{0, 1, 0, 0, 0, 1, 0},
+	{0, 5, 0, 0, 0, 0, 0},
+	{0, 1, 0, 5, 1, 1, 0},
+	{0, 0, 0, 0, 0, 0, 0},
+}
+
+// pentagonRotationsReversePolar is pentagonRotationsReverse for an index on a polar pentagon
+// when the origin is not on a pentagon.
+var pentagonRotationsReversePolar = [7][7]int{
+	{0, 0, 0, 0, 0, 0, 0},
+	{-1, -1, -1, -1, -1, -1, -1},
+	{0, 1, 1, 1, 1, 1, 1},
+	{0, 1, 0, 0, 0, 1, 0},
+	{0, 1, 0, 0, 1, 1, 1},
+	{0, 1, 0, 5, 1, 1, 0},
+	{0, 1, 1, 0, 1, 1, 1},
+}
+
+// failedDirections marks the pairs of directions, relative to a pentagon base cell, that can't
+// be unfolded: the first is the direction of the origin and the second of the index.
+var failedDirections = [7][7]bool{
+	{false, false, false, false, false, false, false},
+	{false, false, false, false, false, false, false},
+	{false, false, false, false, true, true, false},
+	{false, false, false, false, true, false, true},
+	{false, false, true, true, false, false, false},
+	{false, false, true, false, false, false, true},
+	{false, false, false, true, false, true, false},
+}
+
+// getBaseCellDirection returns the direction from a base cell to a neighboring one, or
+// invalidDigit if they are not neighbors.
+func getBaseCellDirection(originBaseCell, neighboringBaseCell int) direction {
+	for dir := centerDigit; dir < numDigits; dir++ {
+		if baseCellNeighbors[originBaseCell][dir] == neighboringBaseCell {
+			return dir
+		}
+	}
+	return invalidDigit
+}
+
+// cellToLocalIjk returns the ijk+ coordinates of an index in a coordinate space anchored by an
+// origin. It fails if the index is too far from the origin or on the other side of a pentagon.
+func cellToLocalIjk(origin, h h3Index) (coordIJK, bool) {
+	res := getResolution(origin)
+	if res != getResolution(h) {
+		return coordIJK{}, false
+	}
+
+	originBaseCell := getBaseCell(origin)
+	baseCell := getBaseCell(h)
+	if originBaseCell >= numBaseCells || baseCell >= numBaseCells {
+		return coordIJK{}, false
+	}
+
+	// direction from origin base cell to index base cell
+	dir := centerDigit
+	revDir := centerDigit
+	if originBaseCell != baseCell {
+		dir = getBaseCellDirection(originBaseCell, baseCell)
+		if dir == invalidDigit {
+			// base cells are not neighbors, can't unfold
+			return coordIJK{}, false
+		}
+		revDir = getBaseCellDirection(baseCell, originBaseCell)
+	}
+
+	originOnPent := isBaseCellPentagon(originBaseCell)
+	indexOnPent := isBaseCellPentagon(baseCell)
+
+	indexFijk := faceIJK{}
+	if dir != centerDigit {
+		// rotate index into the orientation of the origin base cell; cw because we are undoing
+		// the rotation into that base cell
+		baseCellRotations := baseCellNeighbor60CCWRots[originBaseCell][dir]
+		if indexOnPent {
+			for i := 0; i < baseCellRotations; i++ {
+				h = rotatePent60cw(h)
+				revDir = revDir.rotate60cw()
+				if revDir == kAxesDigit {
+					revDir = revDir.rotate60cw()
+				}
+			}
+		} else {
+			for i := 0; i < baseCellRotations; i++ {
+				h = rotate60cw(h)
+				revDir = revDir.rotate60cw()
+			}
+		}
+	}
+	// face is unused; this produces coordinates in base cell coordinate space
+	h3ToFaceIjkWithInitializedFijk(h, &indexFijk)
+
+	if dir != centerDigit {
+		pentRotations := 0
+		directionRotations := 0
+
+		if originOnPent {
+			originLeadingDigit := leadingNonZeroDigit(origin)
+			if originLeadingDigit == invalidDigit {
+				return coordIJK{}, false
+			}
+			if failedDirections[originLeadingDigit][dir] {
+				return coordIJK{}, false
+			}
+			directionRotations = pentagonRotations[originLeadingDigit][dir]
+			pentRotations = directionRotations
+		} else if indexOnPent {
+			indexLeadingDigit := leadingNonZeroDigit(h)
+			if indexLeadingDigit == invalidDigit {
+				return coordIJK{}, false
+			}
+			if failedDirections[indexLeadingDigit][revDir] {
+				return coordIJK{}, false
+			}
+			pentRotations = pentagonRotations[revDir][indexLeadingDigit]
+		}
+
+		if pentRotations < 0 || directionRotations < 0 {
+			// an invalid k axes digit is present
+			return coordIJK{}, false
+		}
+
+		for i := 0; i < pentRotations; i++ {
+			indexFijk.coord.rotate60cw()
+		}
+
+		offset := coordIJK{}
+		offset.neighbor(dir)
+		// scale offset based on resolution
+		for r := res - 1; r >= 0; r-- {
+			if isResClassIII(r + 1) {
+				offset.downAp7()
+			} else {
+				offset.downAp7r()
+			}
+		}
+
+		for i := 0; i < directionRotations; i++ {
+			offset.rotate60cw()
+		}
+
+		indexFijk.coord = indexFijk.coord.add(offset)
+		indexFijk.coord.normalize()
+	} else if originOnPent && indexOnPent {
+		// the origin and index are on the same pentagon base cell
+		originLeadingDigit := leadingNonZeroDigit(origin)
+		indexLeadingDigit := leadingNonZeroDigit(h)
+		if originLeadingDigit == invalidDigit || indexLeadingDigit == invalidDigit {
+			return coordIJK{}, false
+		}
+		if failedDirections[originLeadingDigit][indexLeadingDigit] {
+			return coordIJK{}, false
+		}
+
+		withinPentagonRotations := pentagonRotations[originLeadingDigit][indexLeadingDigit]
+		for i := 0; i < withinPentagonRotations; i++ {
+			indexFijk.coord.rotate60cw()
+		}
+	}
+
+	return indexFijk.coord, true
+}
+
+// localIjkToCell returns the index at ijk+ coordinates in a coordinate space anchored by an
+// origin. It fails if the coordinates are too far from the origin or on the other side of a
+// pentagon.
+func localIjkToCell(origin h3Index, ijk coordIJK) (h3Index, bool) {
+	res := getResolution(origin)
+	originBaseCell := getBaseCell(origin)
+	if originBaseCell >= numBaseCells {
+		return InvalidH3Index, false
+	}
+	originOnPent := isBaseCellPentagon(originBaseCell)
+
+	// this logic is very similar to faceIjkToH3
+	out := h3Index(h3Init)
+	setMode(&out, hexagonMode)
+	setResolution(&out, res)
+
+	// check for res 0/base cell
+	if res == 0 {
+		dir := unitIjkToDigit(ijk)
+		if dir == invalidDigit {
+			// not a unit vector or zero vector
+			return InvalidH3Index, false
+		}
+		newBaseCell := baseCellNeighbors[originBaseCell][dir]
+		if newBaseCell == invalidBaseCell {
+			// moving in an invalid direction off a pentagon
+			return InvalidH3Index, false
+		}
+		setBaseCell(&out, newBaseCell)
+		return out, true
+	}
+
+	// build the index from finest res up, finding the base cell offset in the origin base
+	// cell's coordinate system
+	for r := res - 1; r >= 0; r-- {
+		lastIJK := ijk
+		var lastCenter coordIJK
+		if isResClassIII(r + 1) {
+			// rotate ccw
+			ijk.upAp7()
+			lastCenter = ijk
+			lastCenter.downAp7()
+		} else {
+			// rotate cw
+			ijk.upAp7r()
+			lastCenter = ijk
+			lastCenter.downAp7r()
+		}
+
+		diff := lastIJK.sub(lastCenter)
+		diff.normalize()
+		setIndexDigit(&out, r+1, unitIjkToDigit(diff))
+	}
+
+	// ijk now holds the coordinates of the base cell in the origin base cell's coordinate system
+	if ijk.i > 1 || ijk.j > 1 || ijk.k > 1 {
+		return InvalidH3Index, false
+	}
+
+	// lookup the correct base cell
+	dir := unitIjkToDigit(ijk)
+	baseCell := baseCellNeighbors[originBaseCell][dir]
+	// pentagon base cells don't border each other, so an invalid base cell here is not one
+	indexOnPent := baseCell != invalidBaseCell && isBaseCellPentagon(baseCell)
+
+	if dir != centerDigit {
+		// if the index is in a warped direction, unwarp the base cell direction; the index
+		// digits may need further rotation
+		pentRotations := 0
+		if originOnPent {
+			originLeadingDigit := leadingNonZeroDigit(origin)
+			if originLeadingDigit == invalidDigit {
+				return InvalidH3Index, false
+			}
+			pentRotations = pentagonRotationsReverse[originLeadingDigit][dir]
+			for i := 0; i < pentRotations; i++ {
+				dir = dir.rotate60ccw()
+			}
+			// the rotations are chosen so that dir is not the deleted direction; if it still
+			// is, there is no index here
+			if dir == kAxesDigit {
+				return InvalidH3Index, false
+			}
+			baseCell = baseCellNeighbors[originBaseCell][dir]
+		}
+
+		// now we can determine the relation between the origin and target base cell
+		baseCellRotations := baseCellNeighbor60CCWRots[originBaseCell][dir]
+
+		// adjust for pentagon warping within the base cell
+		if indexOnPent {
+			revDir := getBaseCellDirection(baseCell, originBaseCell)
+
+			// adjust for the different coordinate space in the two base cells first, since the
+			// pentagon rotations depend on the leading digit in the pentagon's coordinate system
+			for i := 0; i < baseCellRotations; i++ {
+				out = rotate60ccw(out)
+			}
+
+			indexLeadingDigit := leadingNonZeroDigit(out)
+			if isBaseCellPolarPentagon(baseCell) {
+				pentRotations = pentagonRotationsReversePolar[revDir][indexLeadingDigit]
+			} else {
+				pentRotations = pentagonRotationsReverseNonpolar[revDir][indexLeadingDigit]
+			}
+			if pentRotations < 0 {
+				return InvalidH3Index, false
+			}
+
+			for i := 0; i < pentRotations; i++ {
+				out = rotatePent60ccw(out)
+			}
+		} else {
+			if pentRotations < 0 {
+				return InvalidH3Index, false
+			}
+			for i := 0; i < pentRotations; i++ {
+				out = rotate60ccw(out)
+			}
+
+			// adjust for the different coordinate space in the two base cells
+			for i := 0; i < baseCellRotations; i++ {
+				out = rotate60ccw(out)
+			}
+		}
+	} else if originOnPent && indexOnPent {
+		originLeadingDigit := leadingNonZeroDigit(origin)
+		indexLeadingDigit := leadingNonZeroDigit(out)
+		if originLeadingDigit == invalidDigit || indexLeadingDigit == invalidDigit {
+			return InvalidH3Index, false
+		}
+		withinPentagonRotations := pentagonRotationsReverse[originLeadingDigit][indexLeadingDigit]
+		if withinPentagonRotations < 0 {
+			// an invalid k axes digit is present
+			return InvalidH3Index, false
+		}
+		for i := 0; i < withinPentagonRotations; i++ {
+			out = rotate60ccw(out)
+		}
+	}
+
+	// fail if the recovered index is in the deleted k axes subsequence of a pentagon
+	if indexOnPent && leadingNonZeroDigit(out) == kAxesDigit {
+		return InvalidH3Index, false
+	}
+
+	setBaseCell(&out, baseCell)
+	return out, true
+}
+
+// gridDistance returns the number of steps between two indexes, or false if it can't be found
+// because they are too far apart or on opposite sides of a pentagon.
+func gridDistance(origin, h h3Index) (int, bool) {
+	originIjk, ok := cellToLocalIjk(origin, origin)
+	if !ok {
+		return 0, false
+	}
+	hIjk, ok := cellToLocalIjk(origin, h)
+	if !ok {
+		return 0, false
+	}
+	return ijkDistance(originIjk, hIjk), true
+}
+
+// gridPathCells returns the line of indexes from start to end, inclusive, or false if it can't
+// be found because they are too far apart or the line crosses pentagon distortion.
+func gridPathCells(start, end h3Index) ([]h3Index, bool) {
+	distance, ok := gridDistance(start, end)
+	if !ok {
+		return nil, false
+	}
+
+	// the coordinates were already found by gridDistance
+	startIjk, _ := cellToLocalIjk(start, start)
+	endIjk, _ := cellToLocalIjk(start, end)
+
+	// convert to cube coordinates suitable for linear interpolation
+	startIjk.toCube()
+	endIjk.toCube()
+
+	var iStep, jStep, kStep float64
+	if distance != 0 {
+		iStep = float64(endIjk.i-startIjk.i) / float64(distance)
+		jStep = float64(endIjk.j-startIjk.j) / float64(distance)
+		kStep = float64(endIjk.k-startIjk.k) / float64(distance)
+	}
+
+	out := make([]h3Index, distance+1)
+	for n := 0; n <= distance; n++ {
+		current := cubeRound(
+			float64(startIjk.i)+iStep*float64(n),
+			float64(startIjk.j)+jStep*float64(n),
+			float64(startIjk.k)+kStep*float64(n),
+		)
+		current.fromCube()
+		h, ok := localIjkToCell(start, current)
+		if !ok {
+			// the cells between start and end may fall in pentagon distortion
+			return nil, false
+		}
+		out[n] = h
+	}
+	return out, true
+}
+
+func ijkDistance(c1, c2 coordIJK) int {
+	diff := c1.sub(c2)
+	diff.normalize()
+	return maxInt(absInt(diff.i), maxInt(absInt(diff.j), absInt(diff.k)))
+}
+
+// toCube converts ijk+ coordinates to cube coordinates.
+func (c *coordIJK) toCube() {
+	c.i = -c.i + c.k
+	c.j = c.j - c.k
+	c.k = -c.i - c.j
+}
+
+// fromCube converts cube coordinates to ijk+ coordinates.
+func (c *coordIJK) fromCube() {
+	c.i = -c.i
+	c.k = 0
+	c.normalize()
+}
+
+// cubeRound rounds floating point cube coordinates to valid integer cube coordinates.
+func cubeRound(i, j, k float64) coordIJK {
+	ri := int(math.Round(i))
+	rj := int(math.Round(j))
+	rk := int(math.Round(k))
+
+	iDiff := math.Abs(float64(ri) - i)
+	jDiff := math.Abs(float64(rj) - j)
+	kDiff := math.Abs(float64(rk) - k)
+
+	// round, maintaining valid cube coordinates
+	if iDiff > jDiff && iDiff > kDiff {
+		ri = -rj - rk
+	} else if jDiff > kDiff {
+		rj = -ri - rk
+	} else {
+		rk = -ri - rj
+	}
+	return coordIJK{i: ri, j: rj, k: rk}
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
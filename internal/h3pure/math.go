@@ -0,0 +1,242 @@
+package h3pure
+
+import (
+	"math"
+	"math/big"
+)
+
+// The H3 C library uses the platform libm and x87 long double constants. The functions below
+// are correctly rounded, which agrees with libm more often than the math package does, and keep
+// the long double constants in extended precision. This matters where the C code is sensitive
+// to the last bit, such as the clamping in geoAzDistanceRads. Math is done in double-double
+// arithmetic: an unevaluated sum of two float64s with about 106 bits of precision.
+
+type dd struct {
+	hi, lo float64
+}
+
+func twoSum(a, b float64) dd {
+	s := a + b
+	v := s - a
+	return dd{s, (a - (s - v)) + (b - v)}
+}
+
+func quickTwoSum(a, b float64) dd {
+	s := a + b
+	return dd{s, b - (s - a)}
+}
+
+func twoProd(a, b float64) dd {
+	p := a * b
+	return dd{p, math.FMA(a, b, -p)}
+}
+
+func (a dd) add(b dd) dd {
+	s := twoSum(a.hi, b.hi)
+	t := twoSum(a.lo, b.lo)
+	s.lo += t.hi
+	s = quickTwoSum(s.hi, s.lo)
+	s.lo += t.lo
+	return quickTwoSum(s.hi, s.lo)
+}
+
+// addFast is add without the error term of the low parts, which is accurate enough when
+// neither operand cancels the other.
+func (a dd) addFast(b dd) dd {
+	s := twoSum(a.hi, b.hi)
+	return quickTwoSum(s.hi, s.lo+a.lo+b.lo)
+}
+
+func (a dd) neg() dd {
+	return dd{-a.hi, -a.lo}
+}
+
+func (a dd) sub(b dd) dd {
+	return a.add(b.neg())
+}
+
+func (a dd) mul(b dd) dd {
+	p := twoProd(a.hi, b.hi)
+	p.lo += a.hi*b.lo + a.lo*b.hi
+	return quickTwoSum(p.hi, p.lo)
+}
+
+func (a dd) div(b dd) dd {
+	q1 := a.hi / b.hi
+	r := a.sub(b.mul(dd{q1, 0}))
+	q2 := r.hi / b.hi
+	r = r.sub(b.mul(dd{q2, 0}))
+	q3 := r.hi / b.hi
+	return quickTwoSum(q1, q2).add(dd{q3, 0})
+}
+
+// float64 rounds a double-double to the nearest float64.
+func (a dd) float64() float64 {
+	return a.hi + a.lo
+}
+
+// longDouble is a constant rounded to the 64-bit significand of an x87 long double.
+type longDouble dd
+
+func newLongDouble(s string) longDouble {
+	f, _, err := big.ParseFloat(s, 10, 64, big.ToNearestEven)
+	if err != nil {
+		panic(err)
+	}
+	hi, _ := f.Float64()
+	lo, _ := new(big.Float).Sub(f, big.NewFloat(hi)).Float64()
+	return longDouble{hi, lo}
+}
+
+// pi/2 split into three float64s for argument reduction
+var piOver2Parts = [3]float64{1.5707963267948966, 6.123233995736766e-17, -1.4973849048591698e-33}
+
+var invFactorials = newInvFactorials(24)
+
+// maxReducibleArg bounds the arguments reduced exactly by sincos; H3 never gets close.
+const maxReducibleArg = 1e5
+
+// mulLD, divLD, addLD and subLD return x op c computed with the extended precision of c and
+// rounded to float64, as in a C expression that mixes a double with a long double constant.
+func mulLD(x float64, c longDouble) float64 {
+	return dd{x, 0}.mul(dd(c)).float64()
+}
+
+func divLD(x float64, c longDouble) float64 {
+	return dd{x, 0}.div(dd(c)).float64()
+}
+
+func addLD(x float64, c longDouble) float64 {
+	return dd{x, 0}.add(dd(c)).float64()
+}
+
+func subLD(x float64, c longDouble) float64 {
+	return dd{x, 0}.sub(dd(c)).float64()
+}
+
+// lessDD returns whether x < d, and greaterDD returns whether x > d, without rounding d.
+func lessDD(x float64, d dd) bool {
+	return x < d.hi || (x == d.hi && d.lo > 0)
+}
+
+func greaterDD(x float64, d dd) bool {
+	return x > d.hi || (x == d.hi && d.lo < 0)
+}
+
+func newInvFactorials(n int) []dd {
+	f := make([]dd, n)
+	f[0] = dd{1, 0}
+	for i := 1; i < n; i++ {
+		f[i] = f[i-1].div(dd{float64(i), 0})
+	}
+	return f
+}
+
+// sincos returns the sine and cosine of a double-double.
+func sincos(x dd) (dd, dd) {
+	// reduce to r = x - k*pi/2 with |r| <= pi/4
+	k := math.Round(x.hi * 2 / math.Pi)
+	r := x
+	if k != 0 {
+		for _, p := range piOver2Parts {
+			r = r.sub(twoProd(k, p))
+		}
+	}
+
+	// Taylor series in r^2. Terms from r^8 on are below 3e-7, so they are summed in float64
+	// without losing precision in the result.
+	r2 := r.mul(r)
+	x2 := r2.hi
+	st, ct := 0.0, 0.0
+	for i := len(invFactorials) - 1; i >= 9; i -= 2 {
+		st = invFactorials[i].hi - x2*st
+		ct = invFactorials[i-1].hi - x2*ct
+	}
+	s := dd{st, 0}
+	c := dd{ct, 0}
+	for i := 7; i >= 1; i -= 2 {
+		s = s.mul(r2).neg().addFast(invFactorials[i])
+		c = c.mul(r2).neg().addFast(invFactorials[i-1])
+	}
+	s = s.mul(r)
+
+	switch int(k) & 3 {
+	case 1:
+		return c, s.neg()
+	case 2:
+		return s.neg(), c.neg()
+	case 3:
+		return c.neg(), s
+	default:
+		return s, c
+	}
+}
+
+func sin(x float64) float64 {
+	if math.IsNaN(x) || math.Abs(x) > maxReducibleArg {
+		return math.Sin(x)
+	}
+	s, _ := sincos(dd{x, 0})
+	return s.float64()
+}
+
+func cos(x float64) float64 {
+	if math.IsNaN(x) || math.Abs(x) > maxReducibleArg {
+		return math.Cos(x)
+	}
+	_, c := sincos(dd{x, 0})
+	return c.float64()
+}
+
+// sinCos returns sin(x) and cos(x) together, like math.Sincos.
+func sinCos(x float64) (float64, float64) {
+	if math.IsNaN(x) || math.Abs(x) > maxReducibleArg {
+		return math.Sincos(x)
+	}
+	s, c := sincos(dd{x, 0})
+	return s.float64(), c.float64()
+}
+
+func tan(x float64) float64 {
+	if math.IsNaN(x) || math.Abs(x) > maxReducibleArg {
+		return math.Tan(x)
+	}
+	s, c := sincos(dd{x, 0})
+	return s.div(c).float64()
+}
+
+// asin, acos and atan2 refine the float64 result with a Newton step in double-double.
+
+func asin(x float64) float64 {
+	t := math.Asin(x)
+	if math.IsNaN(t) || math.Abs(x) >= 1 || x == 0 {
+		return t
+	}
+	s, c := sincos(dd{t, 0})
+	return dd{t, 0}.sub(s.sub(dd{x, 0}).div(c)).float64()
+}
+
+func acos(x float64) float64 {
+	t := math.Acos(x)
+	if math.IsNaN(t) || math.Abs(x) >= 1 {
+		return t
+	}
+	s, c := sincos(dd{t, 0})
+	return dd{t, 0}.add(c.sub(dd{x, 0}).div(s)).float64()
+}
+
+func atan2(y, x float64) float64 {
+	t := math.Atan2(y, x)
+	if math.IsNaN(t) || math.IsInf(x, 0) || math.IsInf(y, 0) || y == 0 || (x == 0 && y == 0) {
+		return t
+	}
+	s, c := sincos(dd{t, 0})
+	// f(t) = x sin(t) - y cos(t) is zero at the solution, with f'(t) = x cos(t) + y sin(t)
+	f := dd{x, 0}.mul(s).sub(dd{y, 0}.mul(c))
+	fp := dd{x, 0}.mul(c).add(dd{y, 0}.mul(s))
+	return dd{t, 0}.sub(f.div(fp)).float64()
+}
+
+func atan(x float64) float64 {
+	return atan2(x, 1)
+}
@@ -0,0 +1,135 @@
+package placekey
+
+import (
+	"strconv"
+)
+
+// Neighbors returns the Placekeys of the hexagons adjacent to a Placekey, with the what part of
+// the Placekey if keepWhat is true. It returns nil if the Placekey is invalid.
+func Neighbors(placekey string, keepWhat bool) []string {
+	placekeys, _ := NeighborsE(placekey, keepWhat)
+	return placekeys
+}
+
+// NeighborsE returns the Placekeys of the hexagons adjacent to a Placekey, with the what part of
+// the Placekey if keepWhat is true, returning an error if the Placekey is invalid.
+func NeighborsE(placekey string, keepWhat bool) ([]string, error) {
+	return HexRingE(placekey, 1, keepWhat)
+}
+
+// KRing returns the Placekeys of the hexagons within k steps of a Placekey, including the
+// Placekey itself, with the what part of the Placekey if keepWhat is true. It returns nil if the
+// Placekey is invalid or k is negative.
+func KRing(placekey string, k int, keepWhat bool) []string {
+	placekeys, _ := KRingE(placekey, k, keepWhat)
+	return placekeys
+}
+
+// KRingE returns the Placekeys of the hexagons within k steps of a Placekey, including the
+// Placekey itself, with the what part of the Placekey if keepWhat is true, returning an error if
+// the Placekey is invalid or k is negative.
+func KRingE(placekey string, k int, keepWhat bool) ([]string, error) {
+	h3Int, err := parseWhereK(placekey, k)
+	if err != nil {
+		return nil, err
+	}
+	return encodeNeighbors(placekey, h3Indexer.KRing(h3Int, k), keepWhat), nil
+}
+
+// HexRing returns the Placekeys of the hexagons exactly k steps from a Placekey, with the what
+// part of the Placekey if keepWhat is true. It returns nil if the Placekey is invalid or k is
+// negative.
+func HexRing(placekey string, k int, keepWhat bool) []string {
+	placekeys, _ := HexRingE(placekey, k, keepWhat)
+	return placekeys
+}
+
+// HexRingE returns the Placekeys of the hexagons exactly k steps from a Placekey, with the what
+// part of the Placekey if keepWhat is true, returning an error if the Placekey is invalid or k
+// is negative.
+func HexRingE(placekey string, k int, keepWhat bool) ([]string, error) {
+	h3Int, err := parseWhereK(placekey, k)
+	if err != nil {
+		return nil, err
+	}
+	return encodeNeighbors(placekey, h3Indexer.KRingDistances(h3Int, k)[k], keepWhat), nil
+}
+
+// Neighbors returns the Placekeys of the hexagons adjacent to the Placekey, with the what part
+// of the Placekey if keepWhat is true.
+func (pk Placekey) Neighbors(keepWhat bool) []Placekey {
+	return pk.HexRing(1, keepWhat)
+}
+
+// KRing returns the Placekeys of the hexagons within k steps of the Placekey, including the
+// Placekey itself, with the what part of the Placekey if keepWhat is true. It returns nil if k
+// is negative or the Placekey is the zero value.
+func (pk Placekey) KRing(k int, keepWhat bool) []Placekey {
+	if k < 0 || pk.IsZero() {
+		return nil
+	}
+	return pk.neighbors(h3Indexer.KRing(pk.h3, k), keepWhat)
+}
+
+// HexRing returns the Placekeys of the hexagons exactly k steps from the Placekey, with the what
+// part of the Placekey if keepWhat is true. It returns nil if k is negative or the Placekey is
+// the zero value.
+func (pk Placekey) HexRing(k int, keepWhat bool) []Placekey {
+	if k < 0 || pk.IsZero() {
+		return nil
+	}
+	return pk.neighbors(h3Indexer.KRingDistances(pk.h3, k)[k], keepWhat)
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+// validate a Placekey and a number of steps, returning the H3 integer of the Placekey.
+func parseWhereK(placekey string, k int) (uint64, error) {
+	h3Int, err := ParseWhere(placekey)
+	if err != nil {
+		return 0, err
+	}
+	if k < 0 {
+		return 0, &Error{Input: strconv.Itoa(k), Err: ErrInvalidK}
+	}
+	return h3Int, nil
+}
+
+func encodeH3Ints(h3Ints []uint64) []string {
+	placekeys := make([]string, len(h3Ints))
+	for i, h := range h3Ints {
+		placekeys[i] = encodeH3Int(h)
+	}
+	return placekeys
+}
+
+// encode the cells around a Placekey, prefixed with its what part if keepWhat is true.
+func encodeNeighbors(placekey string, h3Ints []uint64, keepWhat bool) []string {
+	placekeys := encodeH3Ints(h3Ints)
+	if what, _ := parsePlacekey(placekey); keepWhat && what != "" {
+		for i := range placekeys {
+			placekeys[i] = what + placekeys[i]
+		}
+	}
+	return placekeys
+}
+
+func h3IntsToPlacekeys(h3Ints []uint64) []Placekey {
+	placekeys := make([]Placekey, len(h3Ints))
+	for i, h := range h3Ints {
+		placekeys[i] = Placekey{h3: h}
+	}
+	return placekeys
+}
+
+// the cells around the Placekey, with its what part if keepWhat is true.
+func (pk Placekey) neighbors(h3Ints []uint64, keepWhat bool) []Placekey {
+	placekeys := h3IntsToPlacekeys(h3Ints)
+	if keepWhat {
+		for i := range placekeys {
+			placekeys[i].what = pk.what
+		}
+	}
+	return placekeys
+}
@@ -0,0 +1,106 @@
+package placekey
+
+// Visit is a Placekey on a traced path and the number of consecutive points of the track that
+// fell in it. Placekeys that the path crosses between two points have a Count of 0.
+type Visit struct {
+	Placekey string
+	Count    int
+}
+
+// Line returns the Placekeys of the line of hexagons from one Placekey to another, inclusive,
+// each adjacent to the one before. The what parts of the Placekeys are not kept. It returns nil
+// if either Placekey is invalid or no line can be found, e.g. because the Placekeys are too far
+// apart or on opposite sides of a pentagon.
+func Line(placekey1, placekey2 string) []string {
+	placekeys, _ := LineE(placekey1, placekey2)
+	return placekeys
+}
+
+// LineE returns the Placekeys of the line of hexagons from one Placekey to another, inclusive,
+// returning an error if either Placekey is invalid or no line can be found.
+func LineE(placekey1, placekey2 string) ([]string, error) {
+	h3Int1, err := ParseWhere(placekey1)
+	if err != nil {
+		return nil, err
+	}
+	h3Int2, err := ParseWhere(placekey2)
+	if err != nil {
+		return nil, err
+	}
+	line := h3Line(h3Int1, h3Int2)
+	if line == nil {
+		return nil, &Error{Input: "(" + placekey1 + ", " + placekey2 + ")", Err: ErrInvalidLine}
+	}
+	return encodeH3Ints(line), nil
+}
+
+// TracePath converts a track of (latitude, longitude) points into the sequence of Placekeys it
+// passes through. Consecutive points in the same Placekey are merged into one Visit, and the
+// Placekeys crossed between points in hexagons that aren't adjacent are filled in with a Line.
+// Points with invalid coordinates are skipped.
+func TracePath(track []LatLng) []Visit {
+	valid := make([]LatLng, 0, len(track))
+	for _, ll := range track {
+		if _, err := FromGeoE(ll.Lat, ll.Lng); err == nil {
+			valid = append(valid, ll)
+		}
+	}
+	visits, _ := TracePathE(valid)
+	return visits
+}
+
+// TracePathE converts a track of (latitude, longitude) points into the sequence of Placekeys it
+// passes through, returning an error if a point has invalid coordinates.
+func TracePathE(track []LatLng) ([]Visit, error) {
+	visits := []Visit{}
+	var last uint64
+	for _, ll := range track {
+		if _, err := FromGeoE(ll.Lat, ll.Lng); err != nil {
+			return nil, err
+		}
+		h3Int := h3Indexer.FromGeo(ll.Lat, ll.Lng, resolution)
+		if len(visits) > 0 && h3Int == last {
+			visits[len(visits)-1].Count++
+			continue
+		}
+		if len(visits) > 0 {
+			// fill in the hexagons crossed since the last point, leaving out both ends
+			line := h3Line(last, h3Int)
+			for i := 1; i < len(line)-1; i++ {
+				visits = append(visits, Visit{Placekey: encodeH3Int(line[i])})
+			}
+		}
+		visits = append(visits, Visit{Placekey: encodeH3Int(h3Int), Count: 1})
+		last = h3Int
+	}
+	return visits, nil
+}
+
+// Line returns the Placekeys of the line of hexagons from the Placekey to another, inclusive,
+// or nil if no line can be found or either Placekey is the zero value. The results have no what
+// part; use WithWhat to keep it.
+func (pk Placekey) Line(other Placekey) []Placekey {
+	if pk.IsZero() || other.IsZero() {
+		return nil
+	}
+	line := h3Line(pk.h3, other.h3)
+	if line == nil {
+		return nil
+	}
+	return h3IntsToPlacekeys(line)
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+// h3Line returns the line of cells between two cells, or nil if the indexer can't find one or
+// it leaves the valid cells, as it can around pentagons.
+func h3Line(h3Int1, h3Int2 uint64) []uint64 {
+	line := h3Indexer.Line(h3Int1, h3Int2)
+	for _, h := range line {
+		if !h3Indexer.IsValid(h) {
+			return nil
+		}
+	}
+	return line
+}
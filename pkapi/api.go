@@ -53,7 +53,7 @@ type Response struct {
 type ErrorResponse struct {
 	Response  *http.Response
 	Message   string `json:"message"`
-	RequestID string `jsonL"request_id`
+	RequestID string `json:"request_id"`
 }
 
 type Rate struct {
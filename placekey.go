@@ -1,6 +1,7 @@
 package placekey
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"regexp"
@@ -10,7 +11,6 @@ import (
 	"github.com/paulmach/orb"
 	"github.com/paulmach/orb/encoding/wkt"
 	"github.com/paulmach/orb/geojson"
-	"github.com/uber/h3-go"
 )
 
 var (
@@ -21,20 +21,23 @@ var (
 	tupleLength      int    = 3
 	paddingChar      string = "a"
 	replacementChars string = "eu"
-	replacementMap          = map[string]string{
-		"prn":   "pre",
-		"f4nny": "f4nne",
-		"tw4t":  "tw4e",
-		"ngr":   "ngu",
-		"dck":   "dce",
-		"vjn":   "vju",
-		"fck":   "fce",
-		"pns":   "pne",
-		"sht":   "she",
-		"kkk":   "kke",
-		"fgt":   "fgu",
-		"dyk":   "dye",
-		"bch":   "bce",
+	// replacements are applied in order when encoding a where part and in reverse order when
+	// decoding one. The order matters for overlapping patterns, e.g. "dykkk", and mirrors
+	// REPLACEMENT_MAP in placekey-py.
+	replacements = [][2]string{
+		{"prn", "pre"},
+		{"f4nny", "f4nne"},
+		{"tw4t", "tw4e"},
+		{"ngr", "ngu"}, // 'u' avoids introducing 'gey'
+		{"dck", "dce"},
+		{"vjn", "vju"}, // 'u' avoids introducing 'jew'
+		{"fck", "fce"},
+		{"pns", "pne"},
+		{"sht", "she"},
+		{"kkk", "kke"},
+		{"fgt", "fgu"}, // 'u' avoids introducing 'fge'
+		{"dyk", "dye"},
+		{"bch", "bce"},
 	}
 	alphabetLength         int
 	headerBits             string
@@ -45,18 +48,47 @@ var (
 	tupleRegex             string
 	whereRegex             *regexp.Regexp
 	whatRegex              *regexp.Regexp
+	alphabetIndex          [256]uint8
+	cleanCodes             *replacer
+	dirtyCodes             *replacer
 )
 
+// ReplacementVersion is the version of the replacement table used to remove unwanted words
+// from encoded where parts. Changing the table changes the Placekeys of some locations, so it
+// is only changed along with placekey-py.
+const ReplacementVersion int = 1
+
+// MaxRadius is the largest radius in meters that FromCircle covers, about 80,000 Placekeys, and
+// the largest buffer around a line that FromLineString covers.
+const MaxRadius float64 = 20000
+
+// MaxChildren is the most Placekeys that Children and Uncompact expand cells into, the
+// resolution 10 children of one resolution 2 cell. A cell at resolution r counts as 7^(10-r).
+const MaxChildren int = 5764801
+
+// whereLength is the length of an encoded where part, e.g. "@dvt-smp-tvz".
+const whereLength int = 12
+
 func init() {
 	alphabet = strings.ToLower(alphabet)
 	alphabetLength = len(alphabet)
-	headerBits = fmt.Sprintf("%064s", strconv.FormatUint(uint64(h3.FromGeo(h3.GeoCoord{0.0, 0.0}, resolution)), 2))[:12]
+	headerBits = fmt.Sprintf("%064s", strconv.FormatUint(h3Indexer.FromGeo(0.0, 0.0, resolution), 2))[:12]
 	baseCellShift = 1 << (3 * 15)
 	unusedResolutionFiller = 1<<(3*(15-baseResolution)) - 1
 	firstTupleRegex = "[" + alphabet + replacementChars + paddingChar + "]{3}"
 	tupleRegex = "[" + alphabet + replacementChars + "]{3}"
 	whereRegex = regexp.MustCompile("^" + strings.Join([]string{firstTupleRegex, tupleRegex, tupleRegex}, "-") + "$")
 	whatRegex = regexp.MustCompile("^[" + alphabet + "]{3}(-[" + alphabet + "]{3})?$")
+
+	for i := 0; i < alphabetLength; i++ {
+		alphabetIndex[alphabet[i]] = uint8(i)
+	}
+	dirty := [][2]string{}
+	for i := len(replacements) - 1; i >= 0; i-- {
+		dirty = append(dirty, [2]string{replacements[i][1], replacements[i][0]})
+	}
+	cleanCodes = newReplacer(replacements)
+	dirtyCodes = newReplacer(dirty)
 }
 
 func getHeaderInt() uint64 {
@@ -74,24 +106,24 @@ func init() {
 
 // FromGeo converts a (latitude, longitude) into a Placekey.
 func FromGeo(lat, lon float64) string {
-	return encodeH3Int(uint64(h3.FromGeo(h3.GeoCoord{lat, lon}, resolution)))
+	return encodeH3Int(h3Indexer.FromGeo(lat, lon, resolution))
 }
 
 // ToGeo converts a Placekey into a (latitude, longitude).
 func ToGeo(placekey string) (float64, float64) {
-	geo := h3.ToGeo(h3.H3Index(ToH3Int(placekey)))
-	return geo.Latitude, geo.Longitude
+	geo := h3Indexer.ToGeo(ToH3Int(placekey))
+	return geo.Lat, geo.Lng
 }
 
 // ToH3 converts a Placekey string into an H3 string.
 func ToH3(placekey string) string {
 	_, where := parsePlacekey(placekey)
-	return h3.ToString(h3.H3Index(decodeToH3Int(where)))
+	return h3Indexer.ToString(decodeToH3Int(where))
 }
 
 // FromH3 converts an H3 hexadecimal string into a Placekey string.
 func FromH3(h3String string) string {
-	return encodeH3Int(uint64(h3.FromString(h3String)))
+	return encodeH3Int(h3Indexer.FromString(h3String))
 }
 
 // GetPrefixDistanceMap returns a map of the length of a shared Placekey prefix to the
@@ -116,6 +148,12 @@ func FromH3Int(h3Int uint64) string {
 	return encodeH3Int(h3Int)
 }
 
+// AppendPlacekey appends the Placekey of an H3 integer to dst and returns the extended buffer.
+// It doesn't allocate if dst has enough capacity.
+func AppendPlacekey(dst []byte, h3Int uint64) []byte {
+	return appendWhere(dst, h3Int)
+}
+
 // ToH3Int converts a Placekey to an H3 integer.
 func ToH3Int(placekey string) uint64 {
 	_, where := parsePlacekey(placekey)
@@ -124,18 +162,13 @@ func ToH3Int(placekey string) uint64 {
 
 // ToHexBoundary returns the Polygon boundary of a Placekey as a slice of (latitude, longitude) coordinates.
 func ToHexBoundary(placekey string) [][]float64 {
-	hexBoundary := [][]float64{}
-	boundary := h3.ToGeoBoundary(h3.H3Index(ToH3Int(placekey)))
-	for _, c := range boundary {
-		hexBoundary = append(hexBoundary, []float64{c.Latitude, c.Longitude})
-	}
-	return hexBoundary
+	return latLngsToSlices(h3Indexer.ToGeoBoundary(ToH3Int(placekey)))
 }
 
 // ToPolygon returns the Polygon boundary of a Placekey as an orb.Polygon.
 func ToPolygon(placekey string) orb.Polygon {
-	boundary := h3.ToGeoBoundary(h3.H3Index(ToH3Int(placekey)))
-	return h3GeoCoordsToOrbPolygon(boundary)
+	boundary := h3Indexer.ToGeoBoundary(ToH3Int(placekey))
+	return latLngsToOrbPolygon(boundary)
 }
 
 // ToGeoJSON returns the Polygon boundary of a Placekey as a GeoJSON Feature string.
@@ -150,37 +183,246 @@ func ToWKT(placekey string) string {
 	return wkt.MarshalString(ToPolygon(placekey))
 }
 
-// // FromPolygon
-// func FromPolygon(p orb.Polygon) {
-// 	// todo: buffer polygon?
-// 	interiorHexes = []
-//     boundaryHexes = []
-// 	candidateHexes := h3.Polyfill(orbPolygonToH3GeoPolygon(p), 10)
-// 	for _, h := range candidateHexes {
-// 		boundary := h3.ToGeoBoundary(h3.H3Index(h))
-// 		hexPoly := h3GeoCoordsToOrbPolygon(boundary)
-// 		// check for contains / intersects
-// 	}
-// 	// ... work in progress!
-// }
+// ToMultiPolygon dissolves the hexagons of a set of Placekeys into the outline of the area they
+// cover, as an orb.MultiPolygon with a Polygon for each group of adjacent hexagons and a hole for
+// each gap enclosed by one. Invalid and duplicate Placekeys are skipped. Longitudes run on
+// continuously from the first Placekey, so an outline across the antimeridian goes past 180 or
+// -180 rather than being split in two.
+func ToMultiPolygon(placekeys []string) orb.MultiPolygon {
+	seen := map[uint64]bool{}
+	h3Ints := make([]uint64, 0, len(placekeys))
+	for _, pk := range placekeys {
+		h3Int, err := ParseWhere(pk)
+		if err != nil || seen[h3Int] {
+			continue
+		}
+		seen[h3Int] = true
+		h3Ints = append(h3Ints, h3Int)
+	}
+	return dissolveHexes(h3Ints)
+}
+
+// ToGeoJSONFeatureCollection returns the dissolved outline of a set of Placekeys as a GeoJSON
+// FeatureCollection string with a Polygon Feature for each group of adjacent hexagons.
+func ToGeoJSONFeatureCollection(placekeys []string) string {
+	fc := geojson.NewFeatureCollection()
+	for _, p := range ToMultiPolygon(placekeys) {
+		fc.Append(geojson.NewFeature(p))
+	}
+	b, _ := fc.MarshalJSON()
+	return string(b)
+}
+
+// ToMultiPolygonWKT returns the dissolved outline of a set of Placekeys as a Well-Known Text
+// (WKT) MultiPolygon string.
+func ToMultiPolygonWKT(placekeys []string) string {
+	return wkt.MarshalString(ToMultiPolygon(placekeys))
+}
+
+// FromPolygon returns the Placekeys of the hexagons that are fully inside a Polygon (interior)
+// and of the hexagons that intersect its edge (boundary). Hexagons that only touch the edge
+// of the Polygon are not included.
+func FromPolygon(p orb.Polygon) ([]string, []string) {
+	interior := []string{}
+	boundary := []string{}
+	if len(p) == 0 || len(p[0]) == 0 {
+		return interior, boundary
+	}
+	candidates, onRings := polygonCandidateHexes(p)
+	idx := newPolygonIndex(p)
+	for _, h := range candidates {
+		if !onRings[h] {
+			// the rest of the polyfill
+			interior = append(interior, encodeH3Int(h))
+			continue
+		}
+		hexPoly := latLngsToOrbPolygon(h3Indexer.ToGeoBoundary(h))
+		if idx.containsPolygon(hexPoly) {
+			interior = append(interior, encodeH3Int(h))
+		} else if idx.overlapsPolygon(hexPoly) {
+			boundary = append(boundary, encodeH3Int(h))
+		}
+	}
+	return interior, boundary
+}
+
+// FromMultiPolygon returns the Placekeys of the hexagons that are fully inside a MultiPolygon
+// (interior) and of the hexagons that intersect its edge (boundary).
+func FromMultiPolygon(mp orb.MultiPolygon) ([]string, []string) {
+	interior := []string{}
+	boundary := []string{}
+	seen := map[string]bool{}
+	edges := []string{}
+	for _, p := range mp {
+		i, b := FromPolygon(p)
+		for _, pk := range i {
+			if !seen[pk] {
+				seen[pk] = true
+				interior = append(interior, pk)
+			}
+		}
+		edges = append(edges, b...)
+	}
+	// a hex on the edge of one polygon may be inside another
+	for _, pk := range edges {
+		if !seen[pk] {
+			seen[pk] = true
+			boundary = append(boundary, pk)
+		}
+	}
+	return interior, boundary
+}
+
+// FromWKT returns the interior and boundary Placekeys of a Polygon, MultiPolygon or
+// GeometryCollection of polygons in Well-Known Text (WKT) format.
+func FromWKT(s string) ([]string, []string, error) {
+	g, err := wkt.Unmarshal(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	mp, err := toMultiPolygon(g)
+	if err != nil {
+		return nil, nil, err
+	}
+	interior, boundary := FromMultiPolygon(mp)
+	return interior, boundary, nil
+}
+
+// FromGeoJSON returns the interior and boundary Placekeys of a GeoJSON Polygon, MultiPolygon,
+// Feature or FeatureCollection. Every geometry must be a Polygon or MultiPolygon: a
+// FeatureCollection with any other feature, such as a Point or a LineString, is rejected as a
+// whole rather than covered in part.
+func FromGeoJSON(b []byte) ([]string, []string, error) {
+	var object struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &object); err != nil {
+		return nil, nil, err
+	}
+
+	geometries := orb.Collection{}
+	switch object.Type {
+	case "FeatureCollection":
+		fc, err := geojson.UnmarshalFeatureCollection(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, f := range fc.Features {
+			geometries = append(geometries, f.Geometry)
+		}
+	case "Feature":
+		f, err := geojson.UnmarshalFeature(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		geometries = append(geometries, f.Geometry)
+	default:
+		g, err := geojson.UnmarshalGeometry(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		geometries = append(geometries, g.Geometry())
+	}
+
+	mp, err := toMultiPolygon(geometries)
+	if err != nil {
+		return nil, nil, err
+	}
+	interior, boundary := FromMultiPolygon(mp)
+	return interior, boundary, nil
+}
+
+// FromCircle returns the Placekeys of the hexagons that are fully inside a circle of a radius
+// in meters around a (latitude, longitude) (interior) and of the hexagons that intersect its
+// edge (boundary). The hexagon containing the center is always included. A negative or NaN
+// radius is treated as 0 and a radius over MaxRadius as MaxRadius.
+func FromCircle(lat, lon, radius float64) ([]string, []string) {
+	interior := []string{}
+	boundary := []string{}
+	radius = clampRadius(radius)
+	center := LatLng{Lat: lat, Lng: lon}
+	origin := h3Indexer.FromGeo(lat, lon, resolution)
+	for _, h := range h3Indexer.KRing(origin, circleK(origin, radius)) {
+		contains, intersects := circleHexRelation(center, radius, h3Indexer.ToGeoBoundary(h))
+		if contains {
+			interior = append(interior, encodeH3Int(h))
+		} else if intersects || h == origin {
+			boundary = append(boundary, encodeH3Int(h))
+		}
+	}
+	return interior, boundary
+}
+
+// FromLineString returns the Placekeys of the hexagons that a LineString passes through, in the
+// order the line reaches them, along with the hexagons that come within a buffer in meters of it.
+// Hexagons that the line only touches are not included unless they are within a positive buffer.
+// Points with invalid coordinates are skipped, a negative or NaN buffer is treated as 0 and a
+// buffer over MaxRadius as MaxRadius.
+func FromLineString(ls orb.LineString, buffer float64) []string {
+	placekeys := []string{}
+	points := make([]orb.Point, 0, len(ls))
+	for _, p := range ls {
+		if _, err := FromGeoE(p[1], p[0]); err == nil {
+			points = append(points, p)
+		}
+	}
+	buffer = clampRadius(buffer)
+
+	seen := map[uint64]bool{}
+	add := func(h uint64) {
+		if !seen[h] {
+			seen[h] = true
+			placekeys = append(placekeys, encodeH3Int(h))
+		}
+	}
+	points = densifyPoints(points)
+	for i, b := range points {
+		a := points[i]
+		if i > 0 {
+			a = points[i-1]
+		}
+		h := h3Indexer.FromGeo(b[1], b[0], resolution)
+		add(h)
+		// the segment from a is no longer than sampleSpacing, so the hexagons within the
+		// buffer of it are within the buffer and the spacing of b
+		for _, c := range h3Indexer.KRing(h, circleK(h, buffer+sampleSpacing)) {
+			if !seen[c] && segmentNearHex(a, b, buffer, h3Indexer.ToGeoBoundary(c)) {
+				add(c)
+			}
+		}
+	}
+	return placekeys
+}
 
-// todo: FromWKT
-// todo: FromGeoJSON
+// FromMultiPoint returns the Placekeys of the hexagons that contain the points of a MultiPoint,
+// without duplicates and in the order of the points. Points with invalid coordinates are skipped.
+func FromMultiPoint(mp orb.MultiPoint) []string {
+	placekeys := []string{}
+	seen := map[uint64]bool{}
+	for _, p := range mp {
+		if _, err := FromGeoE(p[1], p[0]); err != nil {
+			continue
+		}
+		h := h3Indexer.FromGeo(p[1], p[0], resolution)
+		if !seen[h] {
+			seen[h] = true
+			placekeys = append(placekeys, encodeH3Int(h))
+		}
+	}
+	return placekeys
+}
 
 // FormatIsValid returns a boolean for whether or not the format of a Placekey is valid, including
 // checks for valid encoding of location.
 func FormatIsValid(placekey string) bool {
-	what, where := parsePlacekey(placekey)
-	if what != "" {
-		return wherePartIsValid(where) && whatRegex.Match([]byte(what))
-	}
-	return wherePartIsValid(where)
+	_, err := ParseWhere(placekey)
+	return err == nil
 }
 
 // Distance returns the distance in meters between the centers of two Placekeys.
 func Distance(placekey1, placekey2 string) float64 {
-	geo1 := h3.ToGeo(h3.H3Index(ToH3Int(placekey1)))
-	geo2 := h3.ToGeo(h3.H3Index(ToH3Int(placekey2)))
+	geo1 := h3Indexer.ToGeo(ToH3Int(placekey1))
+	geo2 := h3Indexer.ToGeo(ToH3Int(placekey2))
 	return geoDistance(geo1, geo2)
 }
 
@@ -189,24 +431,23 @@ func Distance(placekey1, placekey2 string) float64 {
 
 // split a Placekey in to what and where parts.
 func parsePlacekey(placekey string) (string, string) {
-	if strings.Contains(placekey, "@") {
-		whatwhere := strings.Split(placekey, "@")
-		return whatwhere[0], whatwhere[1]
+	if i := strings.IndexByte(placekey, '@'); i >= 0 {
+		what, where := placekey[:i], placekey[i+1:]
+		if j := strings.IndexByte(where, '@'); j >= 0 {
+			where = where[:j]
+		}
+		return what, where
 	}
 	return "", placekey
 }
 
-func wherePartIsValid(where string) bool {
-	return whereRegex.Match([]byte(where)) && h3.IsValid(h3.H3Index(ToH3Int(where)))
-}
-
-func geoDistance(geo1, geo2 h3.GeoCoord) float64 {
+func geoDistance(geo1, geo2 LatLng) float64 {
 	earthRadius := 6371.0 // km
 
-	lat1 := rad(geo1.Latitude)
-	lon1 := rad(geo1.Longitude)
-	lat2 := rad(geo2.Latitude)
-	lon2 := rad(geo2.Longitude)
+	lat1 := rad(geo1.Lat)
+	lon1 := rad(geo1.Lng)
+	lat2 := rad(geo2.Lat)
+	lon2 := rad(geo2.Lng)
 
 	havLat := 0.5 * (1 - math.Cos(lat1-lat2))
 	havLon := 0.5 * (1 - math.Cos(lon1-lon2))
@@ -214,57 +455,58 @@ func geoDistance(geo1, geo2 h3.GeoCoord) float64 {
 	return 2.0 * earthRadius * math.Asin(radical) * 1000
 }
 
-// shorten an H3 integer to only include location data up to the base resolution
 func encodeH3Int(h3Int uint64) string {
-	shortH3Int := shortenH3Int(h3Int)
-	encodedShortH3 := encodeShortInt(int(shortH3Int))
+	var buf [whereLength]byte
+	return string(appendWhere(buf[:0], h3Int))
+}
 
-	cleanEncodedShortH3 := cleanString(encodedShortH3)
-	if len(cleanEncodedShortH3) <= codeLength {
-		cleanEncodedShortH3 = strings.Repeat(paddingChar, codeLength-len(cleanEncodedShortH3)) + cleanEncodedShortH3
-	}
+// appendWhere appends the encoded where part of an H3 integer, including the leading "@".
+func appendWhere(dst []byte, h3Int uint64) []byte {
+	// shorten an H3 integer to only include location data up to the base resolution
+	shortH3Int := shortenH3Int(h3Int)
 
-	tuples := []string{}
-	for i := 0; i < len(cleanEncodedShortH3); i += tupleLength {
-		tuples = append(tuples, cleanEncodedShortH3[i:i+tupleLength])
+	var code [9]byte
+	for i := len(code) - 1; i >= 0; i-- {
+		code[i] = alphabet[shortH3Int%uint64(alphabetLength)]
+		shortH3Int /= uint64(alphabetLength)
+		if shortH3Int == 0 {
+			for i--; i >= 0; i-- {
+				code[i] = paddingChar[0]
+			}
+		}
 	}
+	cleanCodes.replace(code[:])
 
-	return "@" + strings.Join(tuples, "-")
-}
-
-func encodeShortInt(x int) string {
-	if x == 0 {
-		return string(alphabet[0])
-	}
-	res := ``
-	for x > 0 {
-		remainder := x % alphabetLength
-		res = string(alphabet[remainder]) + res
-		x = x / alphabetLength
+	dst = append(dst, '@')
+	for i := 0; i < len(code); i += tupleLength {
+		if i > 0 {
+			dst = append(dst, '-')
+		}
+		dst = append(dst, code[i:i+tupleLength]...)
 	}
-	return res
+	return dst
 }
 
 func decodeToH3Int(wherePart string) uint64 {
-	code := stripEncoding(wherePart)
-	dirtyEncoding := dirtyString(code)
-	shortH3Int := decodeString(dirtyEncoding)
-	return unshortenH3Int(shortH3Int)
-}
-
-func decodeString(s string) uint64 {
-	var val uint64
-	for i := len(s) - 1; i >= 0; i-- {
-		val += power64(uint64(alphabetLength), len(s)-1-i) * uint64(strings.Index(alphabet, string(s[i])))
+	// strip the encoding down to its code, keeping at most the last 9 characters
+	var buf [9]byte
+	n := 0
+	for i := len(wherePart) - 1; i >= 0 && n < len(buf); i-- {
+		c := wherePart[i]
+		if c == '@' || c == '-' || c == paddingChar[0] {
+			continue
+		}
+		n++
+		buf[len(buf)-n] = c
 	}
-	return val
-}
+	code := buf[len(buf)-n:]
+	dirtyCodes.replace(code)
 
-func stripEncoding(s string) string {
-	s = strings.ReplaceAll(s, "@", "")
-	s = strings.ReplaceAll(s, "-", "")
-	s = strings.ReplaceAll(s, paddingChar, "")
-	return s
+	var shortH3Int uint64
+	for _, c := range code {
+		shortH3Int = shortH3Int*uint64(alphabetLength) + uint64(alphabetIndex[c])
+	}
+	return unshortenH3Int(shortH3Int)
 }
 
 func shortenH3Int(h3Int uint64) uint64 {
@@ -281,31 +523,40 @@ func unshortenH3Int(shortH3Int uint64) uint64 {
 	return rebuiltInt
 }
 
-func cleanString(s string) string {
-	// Replacement should be in order
-	for k, v := range replacementMap {
-		if strings.ContainsAny(s, k) {
-			s = strings.ReplaceAll(s, k, v)
-		}
-	}
-	return s
+// replacer rewrites every occurrence of a set of patterns in place, one pattern at a time.
+// Each pattern is replaced by a string of the same length that differs only in its last
+// character, so no allocation is needed.
+type replacer struct {
+	patterns []string
+	lasts    []byte
+	// starts[c] has bit i set if patterns[i] starts with byte c
+	starts [256]uint32
 }
 
-func dirtyString(s string) string {
-	// Replacement should be in (reversed) order
-	for k, v := range replacementMap {
-		if strings.ContainsAny(s, v) {
-			s = strings.ReplaceAll(s, v, k)
+func newReplacer(pairs [][2]string) *replacer {
+	r := &replacer{}
+	for i, pair := range pairs {
+		from, to := pair[0], pair[1]
+		if len(from) != len(to) || from[:len(from)-1] != to[:len(to)-1] {
+			panic("placekey: replacement " + from + " -> " + to + " must only change the last character")
 		}
+		r.patterns = append(r.patterns, from)
+		r.lasts = append(r.lasts, to[len(to)-1])
+		r.starts[from[0]] |= 1 << uint(i)
 	}
-	return s
+	return r
 }
 
-func power64(base uint64, exponent int) uint64 {
-	if exponent == 0 {
-		return 1
+func (r *replacer) replace(b []byte) {
+	for i, pattern := range r.patterns {
+		bit := uint32(1) << uint(i)
+		for j := 0; j+len(pattern) <= len(b); j++ {
+			if r.starts[b[j]]&bit != 0 && string(b[j:j+len(pattern)]) == pattern {
+				b[j+len(pattern)-1] = r.lasts[i]
+				j += len(pattern) - 1
+			}
+		}
 	}
-	return (base * power64(base, exponent-1))
 }
 
 ///////////////////////////////////////////////////
@@ -322,12 +573,20 @@ func deg(r float64) float64 {
 ///////////////////////////////////////////////////
 ///////////////////////////////////////////////////
 
-func h3GeoCoordsToOrbPolygon(gc []h3.GeoCoord) orb.Polygon {
+func latLngsToSlices(ll []LatLng) [][]float64 {
+	latlngs := [][]float64{}
+	for _, c := range ll {
+		latlngs = append(latlngs, []float64{c.Lat, c.Lng})
+	}
+	return latlngs
+}
+
+func latLngsToOrbPolygon(ll []LatLng) orb.Polygon {
 	ring := orb.Ring{}
-	for _, c := range gc {
-		ring = append(ring, orb.Point{c.Longitude, c.Latitude})
+	for _, c := range ll {
+		ring = append(ring, orb.Point{c.Lng, c.Lat})
 	}
-	ring = append(ring, orb.Point{gc[0].Longitude, gc[0].Latitude})
+	ring = append(ring, orb.Point{ll[0].Lng, ll[0].Lat})
 
 	// enforce right-hand rule that exterior rings must be counterclockwise
 	if ring.Orientation() == orb.CW {
@@ -336,24 +595,57 @@ func h3GeoCoordsToOrbPolygon(gc []h3.GeoCoord) orb.Polygon {
 	return orb.Polygon{ring}
 }
 
-func orbPolygonToH3GeoPolygon(p orb.Polygon) h3.GeoPolygon {
+// convert a Polygon into an exterior ring (geofence) and holes of (latitude, longitude) coordinates.
+func orbPolygonToLatLngs(p orb.Polygon) ([]LatLng, [][]LatLng) {
 
-	gc := []h3.GeoCoord{}
+	geofence := []LatLng{}
 
 	if len(p) == 0 {
-		return h3.GeoPolygon{}
+		return geofence, nil
 	}
 
-	if p[0].Orientation() == orb.CW {
-		p[0].Reverse()
+	for _, c := range p[0] {
+		geofence = append(geofence, LatLng{Lat: c[1], Lng: c[0]})
 	}
 
-	for _, c := range p[0] {
-		gc = append(gc, h3.GeoCoord{Latitude: c[1], Longitude: c[0]})
+	// reverse the copy rather than the caller's ring
+	if p[0].Orientation() == orb.CW {
+		for i, j := 0, len(geofence)-1; i < j; i, j = i+1, j-1 {
+			geofence[i], geofence[j] = geofence[j], geofence[i]
+		}
 	}
-	return h3.GeoPolygon{Geofence: gc}
-}
 
-func h3GeoCoordsToH3GeoPolygon(gc []h3.GeoCoord) h3.GeoPolygon {
-	return h3.GeoPolygon{Geofence: gc}
+	holes := [][]LatLng{}
+	for _, r := range p[1:] {
+		hole := []LatLng{}
+		for _, c := range r {
+			hole = append(hole, LatLng{Lat: c[1], Lng: c[0]})
+		}
+		holes = append(holes, hole)
+	}
+	return geofence, holes
+}
+
+// flatten polygonal geometries into a single MultiPolygon.
+func toMultiPolygon(g orb.Geometry) (orb.MultiPolygon, error) {
+	switch g := g.(type) {
+	case orb.Polygon:
+		return orb.MultiPolygon{g}, nil
+	case orb.MultiPolygon:
+		return g, nil
+	case orb.Collection:
+		mp := orb.MultiPolygon{}
+		for _, c := range g {
+			m, err := toMultiPolygon(c)
+			if err != nil {
+				return nil, err
+			}
+			mp = append(mp, m...)
+		}
+		return mp, nil
+	case nil:
+		return nil, fmt.Errorf("placekey: missing geometry")
+	default:
+		return nil, fmt.Errorf("placekey: unsupported geometry type %s", g.GeoJSONType())
+	}
 }
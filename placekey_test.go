@@ -1,8 +1,19 @@
 package placekey
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"math"
+	"math/rand"
+	"reflect"
+	"sort"
 	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkt"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/planar"
 )
 
 func TestToGeo(t *testing.T) {
@@ -31,6 +42,72 @@ func TestDistance(t *testing.T) {
 	}
 }
 
+func TestDistanceWith(t *testing.T) {
+	got := DistanceWith("@dvt-smp-tvz", "@5vg-7gq-tjv", DistanceOptions{})
+	if got != Distance("@dvt-smp-tvz", "@5vg-7gq-tjv") {
+		t.Errorf(`DistanceWith("@dvt-smp-tvz", "@5vg-7gq-tjv", Haversine) = %f; wanted %f`, got, Distance("@dvt-smp-tvz", "@5vg-7gq-tjv"))
+	}
+
+	// Flinders Peak to Buninyong, from Vincenty's paper
+	got = vincentyDistance(LatLng{Lat: -37.95103342, Lng: 144.42486789}, LatLng{Lat: -37.65282114, Lng: 143.92649554})
+	if diff := math.Abs(got - 54972.271); diff > 0.001 {
+		t.Errorf(`vincentyDistance(Flinders Peak, Buninyong) = %f; wanted 54972.271`, got)
+	}
+	got = DistanceWith("@5vg-82n-kzz", "@5vg-7gq-tvz", DistanceOptions{Method: Vincenty})
+	if want := Distance("@5vg-82n-kzz", "@5vg-7gq-tvz"); math.Abs(got-want) > want*0.005 {
+		t.Errorf(`DistanceWith("@5vg-82n-kzz", "@5vg-7gq-tvz", Vincenty) = %f; wanted about %f`, got, want)
+	}
+
+	for _, neighbor := range HexRing("@5vg-82n-kzz", 1, false) {
+		if got := DistanceWith("@5vg-82n-kzz", neighbor, DistanceOptions{Method: Boundary}); got != 0 {
+			t.Errorf(`DistanceWith("@5vg-82n-kzz", "%s", Boundary) = %f; wanted 0`, neighbor, got)
+		}
+	}
+	for _, pk := range append(HexRing("@5vg-82n-kzz", 2, false), "@5vg-7gq-tvz") {
+		got := DistanceWith("@5vg-82n-kzz", pk, DistanceOptions{Method: Boundary})
+		if center := Distance("@5vg-82n-kzz", pk); got <= 0 || got >= center {
+			t.Errorf(`DistanceWith("@5vg-82n-kzz", "%s", Boundary) = %f; wanted between 0 and %f`, pk, got, center)
+		}
+	}
+
+	got = DistanceWith("@5vg-82n-kzz", "@5vg-7gq-tvz", DistanceOptions{Method: Grid})
+	if want := float64(len(Line("@5vg-82n-kzz", "@5vg-7gq-tvz")) - 1); got != want {
+		t.Errorf(`DistanceWith("@5vg-82n-kzz", "@5vg-7gq-tvz", Grid) = %f; wanted %f`, got, want)
+	}
+	if _, err := DistanceWithE("@5vg-82n-kzz", "@nh3-yc4-zfz", DistanceOptions{Method: Grid}); !errors.Is(err, ErrInvalidLine) {
+		t.Errorf(`DistanceWithE("@5vg-82n-kzz", "@nh3-yc4-zfz", Grid) error = %v; wanted %v`, err, ErrInvalidLine)
+	}
+	if _, err := DistanceWithE("@5vg-82n-kzz", "@5vg-7gq-tvz", DistanceOptions{Method: 7}); !errors.Is(err, ErrInvalidDistance) {
+		t.Errorf(`DistanceWithE("@5vg-82n-kzz", "@5vg-7gq-tvz", 7) error = %v; wanted %v`, err, ErrInvalidDistance)
+	}
+	if got := DistanceWith("@123-456-789", "@5vg-7gq-tvz", DistanceOptions{}); !math.IsNaN(got) {
+		t.Errorf(`DistanceWith("@123-456-789", "@5vg-7gq-tvz", Haversine) = %f; wanted NaN`, got)
+	}
+}
+
+func TestBearing(t *testing.T) {
+	tests := []struct {
+		lat, lng float64
+		want     float64
+	}{
+		{38.5, -122.44283, 0},
+		{37.7371, -121.5, 90},
+		{37, -122.44283, 180},
+		{37.7371, -123.5, 270},
+	}
+	tolerance := 1.0
+	for _, test := range tests {
+		pk := FromGeo(test.lat, test.lng)
+		got := Bearing("@5vg-82n-kzz", pk)
+		if diff := math.Abs(math.Mod(got-test.want+540, 360) - 180); diff > tolerance {
+			t.Errorf(`Bearing("@5vg-82n-kzz", "%s") = %f; wanted about %f`, pk, got, test.want)
+		}
+	}
+	if _, err := BearingE("@5vg-82n-kzz", "@123-456-789"); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf(`BearingE("@5vg-82n-kzz", "@123-456-789") error = %v; wanted %v`, err, ErrInvalidCharacter)
+	}
+}
+
 func TestFormatIsValid(t *testing.T) {
 	got := FormatIsValid("222-227@dvt-smp-tvz")
 	if got != true {
@@ -48,3 +125,1061 @@ func TestFromGeo(t *testing.T) {
 		t.Errorf(`FromGeo(37.7371, -122.44283) = "%s"; wanted "@5vg-82n-kzz"`, got)
 	}
 }
+
+func TestFromPolygon(t *testing.T) {
+	interior, boundary := FromPolygon(ToPolygon("@5vg-82n-kzz"))
+	if len(interior) != 1 || interior[0] != "@5vg-82n-kzz" || len(boundary) != 0 {
+		t.Errorf(`FromPolygon(ToPolygon("@5vg-82n-kzz")) = %v, %v; wanted [@5vg-82n-kzz], []`, interior, boundary)
+	}
+
+	p := orb.Polygon{orb.Ring{
+		{-122.45, 37.73}, {-122.43, 37.73}, {-122.43, 37.745}, {-122.45, 37.745}, {-122.45, 37.73},
+	}}
+	interior, boundary = FromPolygon(p)
+	if len(interior) == 0 || len(boundary) == 0 {
+		t.Fatalf(`FromPolygon(p) returned %d interior and %d boundary Placekeys; wanted both > 0`, len(interior), len(boundary))
+	}
+	for _, pk := range interior {
+		for _, c := range ToPolygon(pk)[0] {
+			if !planar.PolygonContains(p, c) {
+				t.Errorf(`FromPolygon(p) interior Placekey "%s" is not inside p`, pk)
+			}
+		}
+	}
+	if !contains(boundary, FromGeo(37.73, -122.44)) {
+		t.Errorf(`FromPolygon(p) boundary is missing "%s" on the edge of p`, FromGeo(37.73, -122.44))
+	}
+
+	// a clockwise Polygon has the same Placekeys and is left as it was
+	cw := orb.Polygon{orb.Ring{
+		{-122.45, 37.73}, {-122.45, 37.745}, {-122.43, 37.745}, {-122.43, 37.73}, {-122.45, 37.73},
+	}}
+	want := cw.Clone()
+	cwInterior, cwBoundary := FromPolygon(cw)
+	if !reflect.DeepEqual(cw, want) {
+		t.Errorf(`FromPolygon(cw) changed cw to %v`, cw)
+	}
+	for _, keys := range [][]string{interior, boundary, cwInterior, cwBoundary} {
+		sort.Strings(keys)
+	}
+	if !reflect.DeepEqual(cwInterior, interior) || !reflect.DeepEqual(cwBoundary, boundary) {
+		t.Errorf(`FromPolygon(cw) = %v, %v; wanted %v, %v`, cwInterior, cwBoundary, interior, boundary)
+	}
+
+	// degenerate rings have no interior, only the hexes along them
+	interior, boundary, err := FromWKT("POLYGON((0 0, 0 0, 0 0, 0 0))")
+	if err != nil || len(interior) != 0 || !reflect.DeepEqual(boundary, []string{"@dvt-smp-tvz"}) {
+		t.Errorf(`FromWKT("POLYGON((0 0, 0 0, 0 0, 0 0))") = %v, %v, %v; wanted [], [@dvt-smp-tvz], nil`, interior, boundary, err)
+	}
+	line := orb.Polygon{orb.Ring{{-122.45, 37.73}, {-122.43, 37.745}}}
+	interior, boundary = FromPolygon(line)
+	if want := FromLineString(orb.LineString(line[0]), 0); len(interior) != 0 || len(boundary) < len(want) {
+		t.Errorf(`FromPolygon(line) = %v, %v; wanted no interior and at least %v`, interior, boundary, want)
+	}
+}
+
+// wavyPolygon returns a Polygon around a (latitude, longitude) with n vertices at about r
+// degrees, and a hole of half the size.
+func wavyPolygon(lat, lon, r float64, n int) orb.Polygon {
+	ring := func(r float64, n int) orb.Ring {
+		ring := orb.Ring{}
+		for i := 0; i <= n; i++ {
+			a := 2 * math.Pi * float64(i%n) / float64(n)
+			d := r * (1 + 0.1*math.Sin(7*a))
+			ring = append(ring, orb.Point{lon + d*math.Cos(a)/math.Cos(lat*math.Pi/180), lat + d*math.Sin(a)})
+		}
+		return ring
+	}
+	hole := ring(r/2, n/4)
+	hole.Reverse()
+	return orb.Polygon{ring(r, n), hole}
+}
+
+func TestFromPolygonDetailed(t *testing.T) {
+	p := wavyPolygon(37.7, -122.44, 0.02, 2000)
+	interior, boundary := FromPolygon(p)
+	if len(interior) == 0 || len(boundary) == 0 {
+		t.Fatalf(`FromPolygon(p) returned %d interior and %d boundary Placekeys; wanted both > 0`, len(interior), len(boundary))
+	}
+	found := map[string]bool{}
+	for _, pk := range interior {
+		found[pk] = true
+		for _, c := range ToPolygon(pk)[0] {
+			if !planar.PolygonContains(p, c) {
+				t.Fatalf(`FromPolygon(p) interior Placekey "%s" is not inside p`, pk)
+			}
+		}
+	}
+	for _, pk := range boundary {
+		found[pk] = true
+	}
+
+	// every hex with its center inside p is found, and none with every vertex outside p
+	for _, pk := range KRing(FromGeo(37.7, -122.44), 25, false) {
+		lat, lon := ToGeo(pk)
+		if planar.PolygonContains(p, orb.Point{lon, lat}) && !found[pk] {
+			t.Errorf(`FromPolygon(p) is missing "%s"`, pk)
+		}
+		outside := true
+		for _, c := range ToPolygon(pk)[0] {
+			if planar.PolygonContains(p, c) {
+				outside = false
+			}
+		}
+		if outside && found[pk] {
+			t.Errorf(`FromPolygon(p) has "%s" outside p`, pk)
+		}
+	}
+}
+
+func contains(placekeys []string, placekey string) bool {
+	for _, pk := range placekeys {
+		if pk == placekey {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFromWKT(t *testing.T) {
+	interior, boundary, err := FromWKT(ToWKT("@5vg-82n-kzz"))
+	if err != nil || len(interior) != 1 || interior[0] != "@5vg-82n-kzz" || len(boundary) != 0 {
+		t.Errorf(`FromWKT(ToWKT("@5vg-82n-kzz")) = %v, %v, %v; wanted [@5vg-82n-kzz], [], nil`, interior, boundary, err)
+	}
+	_, _, err = FromWKT("POINT(-122.44283 37.7371)")
+	if err == nil {
+		t.Errorf(`FromWKT("POINT(-122.44283 37.7371)") returned no error; wanted an error`)
+	}
+}
+
+func TestFromGeoJSON(t *testing.T) {
+	interior, boundary, err := FromGeoJSON([]byte(ToGeoJSON("@5vg-82n-kzz")))
+	if err != nil || len(interior) != 1 || interior[0] != "@5vg-82n-kzz" || len(boundary) != 0 {
+		t.Errorf(`FromGeoJSON(ToGeoJSON("@5vg-82n-kzz")) = %v, %v, %v; wanted [@5vg-82n-kzz], [], nil`, interior, boundary, err)
+	}
+	fc := `{"type":"FeatureCollection","features":[` + ToGeoJSON("@5vg-82n-kzz") + `,` + ToGeoJSON("@dvt-smp-tvz") + `]}`
+	interior, _, err = FromGeoJSON([]byte(fc))
+	if err != nil || len(interior) != 2 {
+		t.Errorf(`FromGeoJSON(fc) = %v, %v; wanted 2 interior Placekeys`, interior, err)
+	}
+	mixed := `{"type":"FeatureCollection","features":[` + ToGeoJSON("@5vg-82n-kzz") +
+		`,{"type":"Feature","geometry":{"type":"Point","coordinates":[0,0]},"properties":null}]}`
+	if interior, boundary, err := FromGeoJSON([]byte(mixed)); err == nil {
+		t.Errorf(`FromGeoJSON(mixed) = %v, %v, nil; wanted an error for the Point`, interior, boundary)
+	}
+}
+
+func TestToMultiPolygon(t *testing.T) {
+	got := ToMultiPolygon([]string{"@5vg-82n-kzz", "222-227@5vg-82n-kzz", "@123-456-789"})
+	if want := (orb.MultiPolygon{ToPolygon("@5vg-82n-kzz")}); !reflect.DeepEqual(got, want) {
+		t.Errorf(`ToMultiPolygon({"@5vg-82n-kzz", ...}) = %v; wanted %v`, got, want)
+	}
+	if got := ToMultiPolygon(nil); len(got) != 0 {
+		t.Errorf(`ToMultiPolygon(nil) = %v; wanted []`, got)
+	}
+
+	// a k-ring dissolves into one ring of 6(2k+1) vertices covering the same area as its hexes
+	kring := KRing("@5vg-82n-kzz", 2, false)
+	got = ToMultiPolygon(kring)
+	area := 0.0
+	for _, pk := range kring {
+		area += planar.Area(ToPolygon(pk))
+	}
+	if len(got) != 1 || len(got[0]) != 1 || len(got[0][0]) != 31 {
+		t.Errorf(`ToMultiPolygon(KRing("@5vg-82n-kzz", 2, false)) = %v; wanted one Polygon of 30 vertices`, got)
+	} else if got[0][0].Orientation() != orb.CCW {
+		t.Errorf(`ToMultiPolygon(KRing("@5vg-82n-kzz", 2, false)) exterior ring is not counterclockwise`)
+	}
+	if diff := math.Abs(planar.Area(got) - area); diff > area*1e-9 {
+		t.Errorf(`ToMultiPolygon(KRing("@5vg-82n-kzz", 2, false)) has area %g; wanted %g`, planar.Area(got), area)
+	}
+
+	// rings around a gap have a hole, and hexes apart from them, including those in the hole,
+	// are Polygons of their own
+	placekeys := append(HexRing("@5vg-82n-kzz", 2, false), HexRing("@5vg-82n-kzz", 3, false)...)
+	placekeys = append(placekeys, "@5vg-82n-kzz", "@5vg-7gq-tvz")
+	got = ToMultiPolygon(placekeys)
+	if len(got) != 3 || len(got[0]) != 2 || len(got[1]) != 1 || len(got[2]) != 1 {
+		t.Fatalf(`ToMultiPolygon(placekeys) = %v; wanted a Polygon with a hole and 2 Polygons`, got)
+	}
+	if got[0][1].Orientation() != orb.CW || len(got[0][1]) != 19 {
+		t.Errorf(`ToMultiPolygon(placekeys) hole = %v; wanted the clockwise outline of 18 vertices`, got[0][1])
+	}
+	if !reflect.DeepEqual(got[1], ToPolygon("@5vg-82n-kzz")) {
+		t.Errorf(`ToMultiPolygon(placekeys)[1] = %v; wanted %v`, got[1], ToPolygon("@5vg-82n-kzz"))
+	}
+	if !reflect.DeepEqual(got[2], ToPolygon("@5vg-7gq-tvz")) {
+		t.Errorf(`ToMultiPolygon(placekeys)[2] = %v; wanted %v`, got[2], ToPolygon("@5vg-7gq-tvz"))
+	}
+
+	// hexes on either side of the antimeridian join up into one Polygon next to it
+	across := ToMultiPolygon(KRing(FromGeo(0, 180), 1, false))
+	if len(across) != 1 || len(across[0]) != 1 || len(across[0][0]) != 19 {
+		t.Fatalf(`ToMultiPolygon(KRing(FromGeo(0, 180), 1, false)) = %v; wanted a Polygon of 18 vertices`, across)
+	}
+	if b := across[0].Bound(); b.Max[0]-b.Min[0] > 1 {
+		t.Errorf(`ToMultiPolygon(KRing(FromGeo(0, 180), 1, false)) spans %v; wanted less than 1 degree of longitude`, b)
+	}
+
+	fc, err := geojson.UnmarshalFeatureCollection([]byte(ToGeoJSONFeatureCollection(placekeys)))
+	if err != nil || len(fc.Features) != 3 {
+		t.Errorf(`ToGeoJSONFeatureCollection(placekeys) = %v, %v; wanted 3 Features`, fc, err)
+	}
+	g, err := wkt.Unmarshal(ToMultiPolygonWKT(placekeys))
+	if err != nil || !reflect.DeepEqual(g, got) {
+		t.Errorf(`ToMultiPolygonWKT(placekeys) = %v, %v; wanted %v`, g, err, got)
+	}
+	if _, err := ToMultiPolygonE([]string{"@5vg-82n-kzz", "@123-456-789"}); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf(`ToMultiPolygonE({"@5vg-82n-kzz", "@123-456-789"}) error = %v; wanted %v`, err, ErrInvalidCharacter)
+	}
+}
+
+func TestFromCircle(t *testing.T) {
+	interior, boundary := FromCircle(37.7371, -122.44283, 0)
+	if len(interior) != 0 || len(boundary) != 1 || boundary[0] != "@5vg-82n-kzz" {
+		t.Errorf(`FromCircle(37.7371, -122.44283, 0) = %v, %v; wanted [], [@5vg-82n-kzz]`, interior, boundary)
+	}
+
+	for _, c := range []struct{ lat, lon, radius float64 }{
+		{37.7371, -122.44283, 500},
+		{0.0001, 179.9999, 300},
+		{-45, 20, 2000},
+	} {
+		center := LatLng{Lat: c.lat, Lng: c.lon}
+		interior, boundary := FromCircle(c.lat, c.lon, c.radius)
+		found := map[string]bool{}
+		for _, pk := range interior {
+			found[pk] = true
+			for _, v := range h3Indexer.ToGeoBoundary(ToH3Int(pk)) {
+				if d := geoDistance(center, v); d > c.radius {
+					t.Errorf(`FromCircle(%v, %v, %v) interior %s has a vertex %f away`, c.lat, c.lon, c.radius, pk, d)
+				}
+			}
+		}
+		for _, pk := range boundary {
+			found[pk] = true
+		}
+
+		// every hex with its center in the circle is found, and none is far outside it
+		origin := h3Indexer.FromGeo(c.lat, c.lon, resolution)
+		for _, h := range h3Indexer.KRing(origin, circleK(origin, c.radius)+5) {
+			d := geoDistance(center, h3Indexer.ToGeo(h))
+			if pk := encodeH3Int(h); d <= c.radius && !found[pk] {
+				t.Errorf(`FromCircle(%v, %v, %v) is missing %s %f away`, c.lat, c.lon, c.radius, pk, d)
+			} else if d > c.radius+100 && found[pk] {
+				t.Errorf(`FromCircle(%v, %v, %v) has %s %f away`, c.lat, c.lon, c.radius, pk, d)
+			}
+		}
+		if len(interior) == 0 || len(boundary) == 0 {
+			t.Errorf(`FromCircle(%v, %v, %v) = %d, %d Placekeys; wanted both`, c.lat, c.lon, c.radius, len(interior), len(boundary))
+		}
+	}
+
+	if _, _, err := FromCircleE(37.7371, -122.44283, -1); !errors.Is(err, ErrInvalidRadius) {
+		t.Errorf(`FromCircleE(37.7371, -122.44283, -1) error = %v; wanted %v`, err, ErrInvalidRadius)
+	}
+	if _, _, err := FromCircleE(37.7371, -122.44283, MaxRadius+1); !errors.Is(err, ErrInvalidRadius) {
+		t.Errorf(`FromCircleE(37.7371, -122.44283, MaxRadius+1) error = %v; wanted %v`, err, ErrInvalidRadius)
+	}
+	for _, radius := range []float64{math.NaN(), -1, math.Inf(-1)} {
+		interior, boundary := FromCircle(0, 0, radius)
+		if len(interior) != 0 || len(boundary) != 1 || boundary[0] != FromGeo(0, 0) {
+			t.Errorf(`FromCircle(0, 0, %v) = %v, %v; wanted [], [%s]`, radius, interior, boundary, FromGeo(0, 0))
+		}
+	}
+	for _, radius := range []float64{MaxRadius + 1, 1e12, math.Inf(1)} {
+		if got := clampRadius(radius); got != MaxRadius {
+			t.Errorf(`clampRadius(%v) = %v; wanted MaxRadius`, radius, got)
+		}
+	}
+	if _, _, err := FromCircleE(91, 0, 1); !errors.Is(err, ErrInvalidCoordinate) {
+		t.Errorf(`FromCircleE(91, 0, 1) error = %v; wanted %v`, err, ErrInvalidCoordinate)
+	}
+}
+
+func TestFromLineString(t *testing.T) {
+	got := FromLineString(orb.LineString{{-122.44283, 37.7371}}, 0)
+	if len(got) != 1 || got[0] != "@5vg-82n-kzz" {
+		t.Errorf(`FromLineString({{-122.44283, 37.7371}}, 0) = %v; wanted [@5vg-82n-kzz]`, got)
+	}
+
+	ls := orb.LineString{{-122.44283, 37.7371}, {-122.4300, 37.7450}, {-122.4350, 37.7500}}
+	line := FromLineString(ls, 0)
+	if line[0] != "@5vg-82n-kzz" {
+		t.Errorf(`FromLineString(ls, 0)[0] = "%s"; wanted "@5vg-82n-kzz"`, line[0])
+	}
+	found := map[string]bool{}
+	for _, pk := range line {
+		if found[pk] {
+			t.Errorf(`FromLineString(ls, 0) has %s more than once`, pk)
+		}
+		found[pk] = true
+	}
+	// every hex at a fine sample of the line is found
+	for i := 1; i < len(ls); i++ {
+		for j := 0; j <= 10000; j++ {
+			f := float64(j) / 10000
+			pk := FromGeo(ls[i-1][1]+f*(ls[i][1]-ls[i-1][1]), ls[i-1][0]+f*(ls[i][0]-ls[i-1][0]))
+			if !found[pk] {
+				t.Fatalf(`FromLineString(ls, 0) is missing %s`, pk)
+			}
+		}
+	}
+
+	// a buffer covers the circles around the points of the line, and more with a larger buffer
+	buffered := FromLineString(ls, 200)
+	found = map[string]bool{}
+	for _, pk := range buffered {
+		found[pk] = true
+	}
+	for _, pk := range line {
+		if !found[pk] {
+			t.Errorf(`FromLineString(ls, 200) is missing %s from FromLineString(ls, 0)`, pk)
+		}
+	}
+	for _, p := range ls {
+		interior, boundary := FromCircle(p[1], p[0], 199)
+		for _, pk := range append(interior, boundary...) {
+			if !found[pk] {
+				t.Errorf(`FromLineString(ls, 200) is missing %s near (%f, %f)`, pk, p[1], p[0])
+			}
+		}
+	}
+	if len(FromLineString(ls, 500)) <= len(buffered) {
+		t.Errorf(`FromLineString(ls, 500) has %d Placekeys; wanted more than %d`, len(FromLineString(ls, 500)), len(buffered))
+	}
+
+	// a short segment across the antimeridian stays near it, and matches one away from it
+	across := FromLineString(orb.LineString{{179.999, 0}, {-179.999, 0}}, 0)
+	for _, pk := range across {
+		if _, lon := ToGeo(pk); math.Abs(lon) < 179.99 {
+			t.Errorf(`FromLineString({{179.999, 0}, {-179.999, 0}}, 0) has %s at longitude %f`, pk, lon)
+		}
+	}
+	if n := len(FromLineString(orb.LineString{{9.999, 0}, {10.001, 0}}, 0)); len(across) != n {
+		t.Errorf(`FromLineString({{179.999, 0}, {-179.999, 0}}, 0) has %d Placekeys; wanted %d`, len(across), n)
+	}
+
+	if got := FromLineString(append(orb.LineString{{200, 0}}, ls...), 0); !reflect.DeepEqual(got, line) {
+		t.Errorf(`FromLineString({{200, 0}, ...}, 0) = %v; wanted %v`, got, line)
+	}
+	if _, err := FromLineStringE(ls, -1); !errors.Is(err, ErrInvalidDistance) {
+		t.Errorf(`FromLineStringE(ls, -1) error = %v; wanted %v`, err, ErrInvalidDistance)
+	}
+	if _, err := FromLineStringE(ls, MaxRadius+1); !errors.Is(err, ErrInvalidDistance) {
+		t.Errorf(`FromLineStringE(ls, MaxRadius+1) error = %v; wanted %v`, err, ErrInvalidDistance)
+	}
+	if _, err := FromLineStringE(orb.LineString{{0, 91}}, 0); !errors.Is(err, ErrInvalidCoordinate) {
+		t.Errorf(`FromLineStringE({{0, 91}}, 0) error = %v; wanted %v`, err, ErrInvalidCoordinate)
+	}
+}
+
+func TestFromMultiPoint(t *testing.T) {
+	mp := orb.MultiPoint{{-122.44283, 37.7371}, {-122.4300, 37.7450}, {-122.44283, 37.7371}, {200, 0}}
+	got := FromMultiPoint(mp)
+	want := []string{"@5vg-82n-kzz", "@5vg-82n-x89"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf(`FromMultiPoint(mp) = %v; wanted %v`, got, want)
+	}
+	if _, err := FromMultiPointE(mp); !errors.Is(err, ErrInvalidCoordinate) {
+		t.Errorf(`FromMultiPointE(mp) error = %v; wanted %v`, err, ErrInvalidCoordinate)
+	}
+}
+
+func TestParseWhere(t *testing.T) {
+	tests := []struct {
+		placekey string
+		err      error
+	}{
+		{"222-227@dvt-smp-tvz", nil},
+		{"@dvt-smp-tvz", nil},
+		{"@123-456-789", ErrInvalidCharacter},
+		{"@dvt-smp", ErrInvalidWhere},
+		{"22-227@dvt-smp-tvz", ErrInvalidWhat},
+		{"222-227@dvt-smp-tvz@dvt", ErrInvalidWhere},
+		{"@zzz-zzz-zzz", ErrInvalidH3},
+	}
+	for _, test := range tests {
+		_, err := ParseWhere(test.placekey)
+		if !errors.Is(err, test.err) {
+			t.Errorf(`ParseWhere("%s") error = %v; wanted %v`, test.placekey, err, test.err)
+		}
+	}
+}
+
+func TestFromH3E(t *testing.T) {
+	got, err := FromH3E("8a754e64992ffff")
+	if err != nil || got != "@dvt-smp-tvz" {
+		t.Errorf(`FromH3E("8a754e64992ffff") = "%s", %v; wanted "@dvt-smp-tvz", nil`, got, err)
+	}
+	if _, err := FromH3E("not-h3"); !errors.Is(err, ErrInvalidH3) {
+		t.Errorf(`FromH3E("not-h3") error = %v; wanted %v`, err, ErrInvalidH3)
+	}
+	if _, err := FromH3E("85283473fffffff"); !errors.Is(err, ErrInvalidResolution) {
+		t.Errorf(`FromH3E("85283473fffffff") error = %v; wanted %v`, err, ErrInvalidResolution)
+	}
+}
+
+func TestToGeoE(t *testing.T) {
+	if _, _, err := ToGeoE("@5vg-82n-kzz"); err != nil {
+		t.Errorf(`ToGeoE("@5vg-82n-kzz") error = %v; wanted nil`, err)
+	}
+	if _, _, err := ToGeoE("@5vg-82n"); !errors.Is(err, ErrInvalidWhere) {
+		t.Errorf(`ToGeoE("@5vg-82n") error = %v; wanted %v`, err, ErrInvalidWhere)
+	}
+}
+
+func TestParse(t *testing.T) {
+	pk, err := Parse("222-227@dvt-smp-tvz")
+	if err != nil {
+		t.Fatalf(`Parse("222-227@dvt-smp-tvz") error = %v; wanted nil`, err)
+	}
+	if got := pk.String(); got != "222-227@dvt-smp-tvz" {
+		t.Errorf(`String() = "%s"; wanted "222-227@dvt-smp-tvz"`, got)
+	}
+	if got := pk.What(); got != "222-227" {
+		t.Errorf(`What() = "%s"; wanted "222-227"`, got)
+	}
+	if got := pk.Where(); got != "dvt-smp-tvz" {
+		t.Errorf(`Where() = "%s"; wanted "dvt-smp-tvz"`, got)
+	}
+	if got := pk.H3(); got != "8a754e64992ffff" {
+		t.Errorf(`H3() = "%s"; wanted "8a754e64992ffff"`, got)
+	}
+	lat, lng := pk.LatLng()
+	if wantLat, wantLng := ToGeo("@dvt-smp-tvz"); lat != wantLat || lng != wantLng {
+		t.Errorf(`LatLng() = (%f, %f); wanted (%f, %f)`, lat, lng, wantLat, wantLng)
+	}
+	if got := len(pk.Boundary()); got != 6 {
+		t.Errorf(`len(Boundary()) = %d; wanted 6`, got)
+	}
+	if _, err := Parse("@123-456-789"); err == nil {
+		t.Errorf(`Parse("@123-456-789") error = nil; wanted an error`)
+	}
+
+	// the zero Placekey is not cell 0
+	var zero Placekey
+	if got := zero.String(); got != "" {
+		t.Errorf(`Placekey{}.String() = "%s"; wanted ""`, got)
+	}
+	if got := zero.Where(); got != "" {
+		t.Errorf(`Placekey{}.Where() = "%s"; wanted ""`, got)
+	}
+	if got := zero.H3(); got != "" {
+		t.Errorf(`Placekey{}.H3() = "%s"; wanted ""`, got)
+	}
+	if lat, lng := zero.LatLng(); !math.IsNaN(lat) || !math.IsNaN(lng) {
+		t.Errorf(`Placekey{}.LatLng() = (%f, %f); wanted (NaN, NaN)`, lat, lng)
+	}
+	if zero.Boundary() != nil || zero.Polygon() != nil {
+		t.Errorf(`Placekey{}.Boundary(), Polygon() = %v, %v; wanted nil, nil`, zero.Boundary(), zero.Polygon())
+	}
+	if d := pk.Distance(zero); !math.IsNaN(d) {
+		t.Errorf(`Distance(Placekey{}) = %f; wanted NaN`, d)
+	}
+	if b := zero.Bearing(pk); !math.IsNaN(b) {
+		t.Errorf(`Placekey{}.Bearing(pk) = %f; wanted NaN`, b)
+	}
+	if n := zero.SharedPrefixLength(zero); n != 0 {
+		t.Errorf(`Placekey{}.SharedPrefixLength(Placekey{}) = %d; wanted 0`, n)
+	}
+	if got := zero.WithWhat(pk.what); !got.IsZero() {
+		t.Errorf(`Placekey{}.WithWhat(...) = "%s"; wanted the zero Placekey`, got)
+	}
+}
+
+func TestParseWhat(t *testing.T) {
+	w, err := ParseWhat("222-227")
+	if err != nil || w.Address != "222" || w.POI != "227" {
+		t.Errorf(`ParseWhat("222-227") = %+v, %v; wanted {Address:222 POI:227}, nil`, w, err)
+	}
+	w, err = NewWhat("222", "")
+	if err != nil || w.String() != "222" {
+		t.Errorf(`NewWhat("222", "") = "%s", %v; wanted "222", nil`, w, err)
+	}
+	if _, err := NewWhat("", "227"); !errors.Is(err, ErrInvalidWhat) {
+		t.Errorf(`NewWhat("", "227") error = %v; wanted %v`, err, ErrInvalidWhat)
+	}
+	if _, err := ParseWhat("2a2"); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf(`ParseWhat("2a2") error = %v; wanted %v`, err, ErrInvalidCharacter)
+	}
+}
+
+func TestSameAddress(t *testing.T) {
+	if !SameAddress("222-227@dvt-smp-tvz", "222-228@dvt-smp-tvz") {
+		t.Errorf(`SameAddress("222-227@dvt-smp-tvz", "222-228@dvt-smp-tvz") = false; wanted true`)
+	}
+	if SameAddress("222-227@dvt-smp-tvz", "223-227@dvt-smp-tvz") {
+		t.Errorf(`SameAddress("222-227@dvt-smp-tvz", "223-227@dvt-smp-tvz") = true; wanted false`)
+	}
+	if SamePOI("222-227@dvt-smp-tvz", "222-228@dvt-smp-tvz") {
+		t.Errorf(`SamePOI("222-227@dvt-smp-tvz", "222-228@dvt-smp-tvz") = true; wanted false`)
+	}
+	if !SamePOI("222-227@dvt-smp-tvz", "222-227@dvt-smp-tvz") {
+		t.Errorf(`SamePOI("222-227@dvt-smp-tvz", "222-227@dvt-smp-tvz") = false; wanted true`)
+	}
+}
+
+func TestKRing(t *testing.T) {
+	ring := KRing("222-227@5vg-82n-kzz", 1, false)
+	if len(ring) != 7 || ring[0] != "@5vg-82n-kzz" {
+		t.Fatalf(`KRing("222-227@5vg-82n-kzz", 1, false) = %v; wanted @5vg-82n-kzz and its 6 neighbors`, ring)
+	}
+	neighbors := Neighbors("@5vg-82n-kzz", false)
+	if len(neighbors) != 6 {
+		t.Fatalf(`Neighbors("@5vg-82n-kzz", false) = %v; wanted 6 Placekeys`, neighbors)
+	}
+	for _, pk := range neighbors {
+		if d := Distance("@5vg-82n-kzz", pk); d < 100 || d > 200 {
+			t.Errorf(`Distance("@5vg-82n-kzz", "%s") = %f; wanted a neighbor about 130m away`, pk, d)
+		}
+		found := false
+		for _, n := range Neighbors(pk, false) {
+			found = found || n == "@5vg-82n-kzz"
+		}
+		if !found {
+			t.Errorf(`Neighbors("%s", false) doesn't contain "@5vg-82n-kzz"`, pk)
+		}
+	}
+	if got := HexRing("@5vg-82n-kzz", 0, false); len(got) != 1 || got[0] != "@5vg-82n-kzz" {
+		t.Errorf(`HexRing("@5vg-82n-kzz", 0, false) = %v; wanted [@5vg-82n-kzz]`, got)
+	}
+	if got := HexRing("@5vg-82n-kzz", 2, false); len(got) != 12 {
+		t.Errorf(`HexRing("@5vg-82n-kzz", 2, false) has %d Placekeys; wanted 12`, len(got))
+	}
+	if got := KRing("@5vg-82n-kzz", 2, false); len(got) != 19 {
+		t.Errorf(`KRing("@5vg-82n-kzz", 2, false) has %d Placekeys; wanted 19`, len(got))
+	}
+
+	// a pentagon has 5 neighbors
+	center := h3Indexer.ToGeo(0x8009fffffffffff)
+	pentagon := FromGeo(center.Lat, center.Lng)
+	if got := Neighbors(pentagon, false); len(got) != 5 {
+		t.Errorf(`Neighbors("%s", false) = %v; wanted 5 Placekeys`, pentagon, got)
+	}
+
+	if _, err := KRingE("@5vg-82n-kzz", -1, false); !errors.Is(err, ErrInvalidK) {
+		t.Errorf(`KRingE("@5vg-82n-kzz", -1, false) error = %v; wanted %v`, err, ErrInvalidK)
+	}
+	if _, err := HexRingE("@5vg-82n", 1, false); !errors.Is(err, ErrInvalidWhere) {
+		t.Errorf(`HexRingE("@5vg-82n", 1, false) error = %v; wanted %v`, err, ErrInvalidWhere)
+	}
+	if got := Neighbors("@123-456-789", false); got != nil {
+		t.Errorf(`Neighbors("@123-456-789", false) = %v; wanted nil`, got)
+	}
+
+	pk, _ := Parse("222-227@5vg-82n-kzz")
+	for i, n := range pk.Neighbors(false) {
+		if n.String() != neighbors[i] {
+			t.Errorf(`Neighbors(false)[%d] = "%s"; wanted "%s"`, i, n, neighbors[i])
+		}
+	}
+	if got := (Placekey{}).KRing(1, false); got != nil {
+		t.Errorf(`Placekey{}.KRing(1, false) = %v; wanted nil`, got)
+	}
+
+	// the what part is kept only when asked for
+	for i, n := range pk.Neighbors(true) {
+		if got := n.String(); got != "222-227"+neighbors[i] {
+			t.Errorf(`Neighbors(true)[%d] = "%s"; wanted "222-227%s"`, i, got, neighbors[i])
+		}
+	}
+	if got := pk.KRing(1, true); len(got) != 7 || got[0] != pk {
+		t.Errorf(`KRing(1, true) = %v; wanted %s and its 6 neighbors`, got, pk)
+	}
+	if got := pk.HexRing(2, true); len(got) != 12 || got[0].What() != "222-227" {
+		t.Errorf(`HexRing(2, true) = %v; wanted 12 Placekeys with what part 222-227`, got)
+	}
+	ring = KRing("222-227@5vg-82n-kzz", 1, true)
+	if len(ring) != 7 || ring[0] != "222-227@5vg-82n-kzz" {
+		t.Errorf(`KRing("222-227@5vg-82n-kzz", 1, true) = %v; wanted 222-227@5vg-82n-kzz and its 6 neighbors`, ring)
+	}
+	for i, n := range Neighbors("222-227@5vg-82n-kzz", true) {
+		if n != "222-227"+neighbors[i] {
+			t.Errorf(`Neighbors("222-227@5vg-82n-kzz", true)[%d] = "%s"; wanted "222-227%s"`, i, n, neighbors[i])
+		}
+	}
+	if got := HexRing("@5vg-82n-kzz", 1, true); !reflect.DeepEqual(got, neighbors) {
+		t.Errorf(`HexRing("@5vg-82n-kzz", 1, true) = %v; wanted %v`, got, neighbors)
+	}
+}
+
+func TestSharedPrefixLength(t *testing.T) {
+	tests := []struct {
+		placekey1, placekey2 string
+		want                 int
+	}{
+		{"@5vg-82n-kzz", "222-227@5vg-82n-kzz", 9},
+		{"@5vg-82n-kzz", "@5vg-82n-k9f", 7},
+		{"@5vg-82n-kzz", "@5vg-7gq-tvz", 3},
+		{"@5vg-82n-kzz", "@5ys-rsx-4jv", 1},
+		{"@5vg-82n-kzz", "@123-456-789", 0},
+	}
+	for _, test := range tests {
+		if got := SharedPrefixLength(test.placekey1, test.placekey2); got != test.want {
+			t.Errorf(`SharedPrefixLength("%s", "%s") = %d; wanted %d`, test.placekey1, test.placekey2, got, test.want)
+		}
+	}
+
+	// points sharing a prefix are within the distance for it
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		lat, lon := r.Float64()*180-90, r.Float64()*360-180
+		d := math.Pow(10, -r.Float64()*5)
+		pk1, pk2 := FromGeo(lat, lon), FromGeo(math.Max(-90, math.Min(90, lat+d*(r.Float64()-0.5))), lon+d*(r.Float64()-0.5))
+		n := SharedPrefixLength(pk1, pk2)
+		if dist := Distance(pk1, pk2); dist > MaxDistanceForPrefix(n) {
+			t.Errorf(`Distance("%s", "%s") = %f; wanted at most MaxDistanceForPrefix(%d) = %f`, pk1, pk2, dist, n, MaxDistanceForPrefix(n))
+		}
+	}
+
+	if got := MaxDistanceForPrefix(12); got != 63.47 {
+		t.Errorf(`MaxDistanceForPrefix(12) = %f; wanted 63.47`, got)
+	}
+}
+
+func TestGroupByPrefix(t *testing.T) {
+	placekeys := []string{"@5vg-82n-kzz", "@5ys-rsx-4jv", "222-227@5vg-82n-k9f", "@123-456-789", "@5vg-7gq-tvz"}
+
+	groups := GroupByPrefix(placekeys, 2000)
+	want := [][]string{{"@5vg-82n-kzz", "222-227@5vg-82n-k9f"}, {"@5ys-rsx-4jv"}, {"@5vg-7gq-tvz"}}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf(`GroupByPrefix(%v, 2000) = %v; wanted %v`, placekeys, groups, want)
+	}
+	for _, g := range groups {
+		for _, pk := range g[1:] {
+			if d := Distance(g[0], pk); d > 2000 {
+				t.Errorf(`Distance("%s", "%s") = %f; wanted at most 2000`, g[0], pk, d)
+			}
+		}
+	}
+
+	if got := GroupByPrefix(placekeys, 1e8); len(got) != 1 || len(got[0]) != 4 {
+		t.Errorf(`GroupByPrefix(%v, 1e8) = %v; wanted a single group of 4`, placekeys, got)
+	}
+	if got := GroupByPrefix(placekeys, 10); len(got) != 4 {
+		t.Errorf(`GroupByPrefix(%v, 10) = %v; wanted 4 groups`, placekeys, got)
+	}
+
+	if _, err := GroupByPrefixE(placekeys, 2000); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf(`GroupByPrefixE(%v, 2000) error = %v; wanted %v`, placekeys, err, ErrInvalidCharacter)
+	}
+	if _, err := GroupByPrefixE(nil, -1); !errors.Is(err, ErrInvalidDistance) {
+		t.Errorf(`GroupByPrefixE(nil, -1) error = %v; wanted %v`, err, ErrInvalidDistance)
+	}
+}
+
+func TestParentChildren(t *testing.T) {
+	tests := []struct {
+		res  int
+		want string
+	}{
+		{10, "8a2830953157fff"},
+		{8, "8828309531fffff"},
+		{0, "8029fffffffffff"},
+	}
+	for _, test := range tests {
+		if got := Parent("222-227@5vg-82n-kzz", test.res); got != test.want {
+			t.Errorf(`Parent("222-227@5vg-82n-kzz", %d) = "%s"; wanted "%s"`, test.res, got, test.want)
+		}
+	}
+
+	children := Children("8828309531fffff")
+	if len(children) != 49 {
+		t.Fatalf(`Children("8828309531fffff") has %d Placekeys; wanted 49`, len(children))
+	}
+	found := false
+	for _, pk := range children {
+		found = found || pk == "@5vg-82n-kzz"
+		if got := Parent(pk, 8); got != "8828309531fffff" {
+			t.Errorf(`Parent("%s", 8) = "%s"; wanted "8828309531fffff"`, pk, got)
+		}
+	}
+	if !found {
+		t.Errorf(`Children("8828309531fffff") doesn't contain "@5vg-82n-kzz"`)
+	}
+	if got := Children("8a2830953157fff"); len(got) != 1 || got[0] != "@5vg-82n-kzz" {
+		t.Errorf(`Children("8a2830953157fff") = %v; wanted [@5vg-82n-kzz]`, got)
+	}
+	// a pentagon has 1 + 5 * (7^3 - 1) / 6 children three resolutions down
+	if got := Children("870800000ffffff"); len(got) != 286 {
+		t.Errorf(`Children("870800000ffffff") has %d Placekeys; wanted 286`, len(got))
+	}
+
+	if _, err := ParentE("@5vg-82n-kzz", 11); !errors.Is(err, ErrInvalidResolution) {
+		t.Errorf(`ParentE("@5vg-82n-kzz", 11) error = %v; wanted %v`, err, ErrInvalidResolution)
+	}
+	if _, err := ChildrenE("8b2830953150fff"); !errors.Is(err, ErrInvalidResolution) {
+		t.Errorf(`ChildrenE("8b2830953150fff") error = %v; wanted %v`, err, ErrInvalidResolution)
+	}
+	if _, err := ChildrenE("zz"); !errors.Is(err, ErrInvalidH3) {
+		t.Errorf(`ChildrenE("zz") error = %v; wanted %v`, err, ErrInvalidH3)
+	}
+	if _, err := ChildrenE("8009fffffffffff"); !errors.Is(err, ErrTooManyCells) {
+		t.Errorf(`ChildrenE("8009fffffffffff") error = %v; wanted %v`, err, ErrTooManyCells)
+	}
+	if got := Children("8009fffffffffff"); got != nil {
+		t.Errorf(`Children("8009fffffffffff") has %d Placekeys; wanted nil`, len(got))
+	}
+
+	pk, _ := Parse("222-227@5vg-82n-kzz")
+	if got := pk.Parent(8); got != "8828309531fffff" {
+		t.Errorf(`Parent(8) = "%s"; wanted "8828309531fffff"`, got)
+	}
+	if got := (Placekey{}).Parent(8); got != "" {
+		t.Errorf(`Placekey{}.Parent(8) = "%s"; wanted ""`, got)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	placekeys := append(Children("8828309531fffff"), "@5vg-7gq-tvz", "@5vg-82n-kzz", "@123-456-789")
+	compacted := Compact(placekeys)
+	if want := []uint64{0x8a2830828767fff, 0x8828309531fffff}; !reflect.DeepEqual(compacted, want) {
+		t.Errorf(`Compact(...) = %x; wanted %x`, compacted, want)
+	}
+
+	interior, boundary := FromCircle(37.7371, -122.44283, 3000)
+	coverage := append(interior, boundary...)
+	compacted = Compact(coverage)
+	if len(compacted) >= len(coverage)/2 {
+		t.Errorf(`Compact(...) has %d cells for %d Placekeys; wanted fewer`, len(compacted), len(coverage))
+	}
+	uncompacted := Uncompact(compacted)
+	sort.Strings(coverage)
+	sort.Strings(uncompacted)
+	if !reflect.DeepEqual(uncompacted, coverage) {
+		t.Errorf(`Uncompact(Compact(...)) has %d Placekeys; wanted the %d compacted`, len(uncompacted), len(coverage))
+	}
+
+	if _, err := CompactE([]string{"@5vg-82n-kzz", "@123-456-789"}); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf(`CompactE([@5vg-82n-kzz @123-456-789]) error = %v; wanted %v`, err, ErrInvalidCharacter)
+	}
+	if _, err := UncompactE([]uint64{0x8b2830953150fff}); !errors.Is(err, ErrInvalidResolution) {
+		t.Errorf(`UncompactE([8b2830953150fff]) error = %v; wanted %v`, err, ErrInvalidResolution)
+	}
+	if got := Uncompact([]uint64{0x8a2830953157fff, 0}); len(got) != 1 || got[0] != "@5vg-82n-kzz" {
+		t.Errorf(`Uncompact([8a2830953157fff 0]) = %v; wanted [@5vg-82n-kzz]`, got)
+	}
+	// two resolution 2 cells expand into more than MaxChildren Placekeys
+	if _, err := UncompactE([]uint64{0x822837fffffffff, 0x82754ffffffffff}); !errors.Is(err, ErrTooManyCells) {
+		t.Errorf(`UncompactE([822837fffffffff 82754ffffffffff]) error = %v; wanted %v`, err, ErrTooManyCells)
+	}
+	if got := Uncompact([]uint64{0x8009fffffffffff, 0x8a2830953157fff}); len(got) != 1 || got[0] != "@5vg-82n-kzz" {
+		t.Errorf(`Uncompact([8009fffffffffff 8a2830953157fff]) = %v; wanted [@5vg-82n-kzz]`, got)
+	}
+	if got := Compact(nil); len(got) != 0 {
+		t.Errorf(`Compact(nil) = %v; wanted no cells`, got)
+	}
+}
+
+func TestLine(t *testing.T) {
+	if got := Line("222-227@5vg-82n-kzz", "@5vg-82n-kzz"); len(got) != 1 || got[0] != "@5vg-82n-kzz" {
+		t.Errorf(`Line("222-227@5vg-82n-kzz", "@5vg-82n-kzz") = %v; wanted [@5vg-82n-kzz]`, got)
+	}
+
+	line := Line("@5vg-82n-kzz", "@5vg-7gq-tvz")
+	if len(line) != 41 || line[0] != "@5vg-82n-kzz" || line[40] != "@5vg-7gq-tvz" {
+		t.Fatalf(`Line("@5vg-82n-kzz", "@5vg-7gq-tvz") = %v; wanted 41 Placekeys from @5vg-82n-kzz to @5vg-7gq-tvz`, line)
+	}
+	for i := 1; i < len(line); i++ {
+		found := false
+		for _, n := range Neighbors(line[i-1], false) {
+			found = found || n == line[i]
+		}
+		if !found {
+			t.Errorf(`Line(...)[%d] = "%s" isn't a neighbor of "%s"`, i, line[i], line[i-1])
+		}
+	}
+
+	if _, err := LineE("@5vg-82n-kzz", "@nh3-yc4-zfz"); !errors.Is(err, ErrInvalidLine) {
+		t.Errorf(`LineE("@5vg-82n-kzz", "@nh3-yc4-zfz") error = %v; wanted %v`, err, ErrInvalidLine)
+	}
+	if got := Line("@5vg-82n-kzz", "@123-456-789"); got != nil {
+		t.Errorf(`Line("@5vg-82n-kzz", "@123-456-789") = %v; wanted nil`, got)
+	}
+
+	pk1, _ := Parse("222-227@5vg-82n-kzz")
+	pk2, _ := Parse("@5vg-7gq-tvz")
+	for i, pk := range pk1.Line(pk2) {
+		if pk.String() != line[i] {
+			t.Errorf(`Line()[%d] = "%s"; wanted "%s"`, i, pk, line[i])
+		}
+	}
+}
+
+func TestTracePath(t *testing.T) {
+	track := []LatLng{
+		{Lat: 37.7371, Lng: -122.44283},
+		{Lat: 37.73711, Lng: -122.44283},
+		{Lat: 37.7371, Lng: -122.4400},
+		{Lat: 91, Lng: 0},
+		{Lat: 37.7390, Lng: -122.4400},
+	}
+	want := []Visit{
+		{Placekey: "@5vg-82n-kzz", Count: 2},
+		{Placekey: "@5vg-82n-gx5", Count: 0},
+		{Placekey: "@5vg-82n-h3q", Count: 1},
+		{Placekey: "@5vg-82n-guk", Count: 0},
+		{Placekey: "@5vg-82n-gtv", Count: 1},
+	}
+	if got := TracePath(track); !reflect.DeepEqual(got, want) {
+		t.Errorf(`TracePath(%v) = %v; wanted %v`, track, got, want)
+	}
+	if got := TracePath(nil); len(got) != 0 {
+		t.Errorf(`TracePath(nil) = %v; wanted no visits`, got)
+	}
+	if _, err := TracePathE(track); !errors.Is(err, ErrInvalidCoordinate) {
+		t.Errorf(`TracePathE(%v) error = %v; wanted %v`, track, err, ErrInvalidCoordinate)
+	}
+}
+
+func TestPlacekeyJSON(t *testing.T) {
+	var v struct {
+		Placekey Placekey `json:"placekey"`
+	}
+	if err := json.Unmarshal([]byte(`{"placekey":"222-227@dvt-smp-tvz"}`), &v); err != nil {
+		t.Fatalf(`json.Unmarshal error = %v; wanted nil`, err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil || string(b) != `{"placekey":"222-227@dvt-smp-tvz"}` {
+		t.Errorf(`json.Marshal = %s, %v; wanted {"placekey":"222-227@dvt-smp-tvz"}, nil`, b, err)
+	}
+	if err := json.Unmarshal([]byte(`{"placekey":"@123-456-789"}`), &v); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf(`json.Unmarshal error = %v; wanted %v`, err, ErrInvalidCharacter)
+	}
+
+	// only null unmarshals to the zero Placekey
+	if err := json.Unmarshal([]byte(`{"placekey":""}`), &v); !errors.Is(err, ErrInvalidWhere) {
+		t.Errorf(`json.Unmarshal("") error = %v; wanted %v`, err, ErrInvalidWhere)
+	}
+	v.Placekey = Placekey{}
+	if err := json.Unmarshal([]byte(`{"placekey":null}`), &v); err != nil || !v.Placekey.IsZero() {
+		t.Errorf(`json.Unmarshal(null) = "%s", %v; wanted zero Placekey, nil`, v.Placekey, err)
+	}
+	var pk Placekey
+	if err := pk.UnmarshalText([]byte("")); !errors.Is(err, ErrInvalidWhere) {
+		t.Errorf(`UnmarshalText("") error = %v; wanted %v`, err, ErrInvalidWhere)
+	}
+
+	// the zero Placekey round trips through null, and has no text
+	v.Placekey = Placekey{}
+	b, err = json.Marshal(v)
+	if err != nil || string(b) != `{"placekey":null}` {
+		t.Errorf(`json.Marshal(zero) = %s, %v; wanted {"placekey":null}, nil`, b, err)
+	}
+	var back struct {
+		Placekey Placekey `json:"placekey"`
+	}
+	if err := json.Unmarshal(b, &back); err != nil || !back.Placekey.IsZero() {
+		t.Errorf(`json.Unmarshal(json.Marshal(zero)) = "%s", %v; wanted zero Placekey, nil`, back.Placekey, err)
+	}
+	if text, err := (Placekey{}).MarshalText(); !errors.Is(err, ErrInvalidWhere) {
+		t.Errorf(`Placekey{}.MarshalText() = "%s", %v; wanted %v`, text, err, ErrInvalidWhere)
+	}
+	if _, err := json.Marshal(map[Placekey]int{{}: 1}); !errors.Is(err, ErrInvalidWhere) {
+		t.Errorf(`json.Marshal(map[Placekey{}]) error = %v; wanted %v`, err, ErrInvalidWhere)
+	}
+	want, _ := Parse("222-227@dvt-smp-tvz")
+	text, err := want.MarshalText()
+	if err := pk.UnmarshalText(text); err != nil || pk != want {
+		t.Errorf(`UnmarshalText(MarshalText("%s")) = "%s", %v; wanted "%s", nil`, want, pk, err, want)
+	}
+	if err != nil {
+		t.Errorf(`MarshalText("%s") error = %v; wanted nil`, want, err)
+	}
+}
+
+func TestPlacekeySQL(t *testing.T) {
+	var pk Placekey
+	if err := pk.Scan([]byte("@dvt-smp-tvz")); err != nil || pk.String() != "@dvt-smp-tvz" {
+		t.Errorf(`Scan("@dvt-smp-tvz") = "%s", %v; wanted "@dvt-smp-tvz", nil`, pk, err)
+	}
+	if v, err := pk.Value(); err != nil || v != "@dvt-smp-tvz" {
+		t.Errorf(`Value() = %v, %v; wanted "@dvt-smp-tvz", nil`, v, err)
+	}
+	if err := pk.Scan(nil); err != nil || !pk.IsZero() {
+		t.Errorf(`Scan(nil) = "%s", %v; wanted zero Placekey, nil`, pk, err)
+	}
+	if v, err := pk.Value(); err != nil || v != nil {
+		t.Errorf(`Value() = %v, %v; wanted nil, nil`, v, err)
+	}
+	if err := pk.Scan("@dvt-smp"); err == nil {
+		t.Errorf(`Scan("@dvt-smp") error = nil; wanted an error`)
+	}
+	if err := pk.Scan(""); !errors.Is(err, ErrInvalidWhere) {
+		t.Errorf(`Scan("") error = %v; wanted %v`, err, ErrInvalidWhere)
+	}
+	if err := pk.Scan([]byte{}); !errors.Is(err, ErrInvalidWhere) {
+		t.Errorf(`Scan([]byte{}) error = %v; wanted %v`, err, ErrInvalidWhere)
+	}
+}
+
+func TestFromGeoBatch(t *testing.T) {
+	latlngs := []LatLng{{37.7371, -122.44283}, {37.7371, -122.44283}, {0, 0}}
+	got := FromGeoBatch(latlngs, 0)
+	for i, ll := range latlngs {
+		if want := FromGeo(ll.Lat, ll.Lng); got[i] != want {
+			t.Errorf(`FromGeoBatch(...)[%d] = "%s"; wanted "%s"`, i, got[i], want)
+		}
+	}
+}
+
+func TestToGeoBatch(t *testing.T) {
+	latlngs, errs := ToGeoBatch([]string{"@dvt-smp-tvz", "@123-456-789"}, 2)
+	if lat, lng := ToGeo("@dvt-smp-tvz"); latlngs[0] != (LatLng{lat, lng}) || errs[0] != nil {
+		t.Errorf(`ToGeoBatch(...)[0] = %v, %v; wanted {%f %f}, nil`, latlngs[0], errs[0], lat, lng)
+	}
+	if errs[1] == nil {
+		t.Errorf(`ToGeoBatch(...)[1] error = nil; wanted an error`)
+	}
+}
+
+func TestFromGeoStream(t *testing.T) {
+	in := make(chan LatLng)
+	go func() {
+		for i := 0; i < 10000; i++ {
+			in <- LatLng{float64(i%180) - 89.5, float64(i%360) - 179.5}
+		}
+		close(in)
+	}()
+	i := 0
+	for pk := range FromGeoStream(context.Background(), in, 0) {
+		if want := FromGeo(float64(i%180)-89.5, float64(i%360)-179.5); pk != want {
+			t.Fatalf(`FromGeoStream(...)[%d] = "%s"; wanted "%s"`, i, pk, want)
+		}
+		i++
+	}
+	if i != 10000 {
+		t.Errorf(`FromGeoStream(...) sent %d Placekeys; wanted 10000`, i)
+	}
+}
+
+func TestToGeoStream(t *testing.T) {
+	in := make(chan string, 2)
+	in <- "@dvt-smp-tvz"
+	in <- "@123-456-789"
+	close(in)
+	results := []GeoResult{}
+	for r := range ToGeoStream(context.Background(), in, 0) {
+		results = append(results, r)
+	}
+	if len(results) != 2 || results[0].Err != nil || results[1].Err == nil {
+		t.Errorf(`ToGeoStream(...) = %v; wanted a result and an error`, results)
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	// nothing is ever sent, so only the cancel closes the stream
+	ctx, cancel := context.WithCancel(context.Background())
+	out := FromGeoStream(ctx, make(chan LatLng), 1)
+	cancel()
+	if _, ok := <-out; ok {
+		t.Errorf(`FromGeoStream(...) sent a Placekey after cancel; wanted a closed channel`)
+	}
+
+	// the consumer stops reading with more results to send than the output channel holds
+	ctx, cancel = context.WithCancel(context.Background())
+	in := make(chan string, 3*streamChunkSize)
+	for i := 0; i < cap(in); i++ {
+		in <- "@dvt-smp-tvz"
+	}
+	results := ToGeoStream(ctx, in, 1)
+	<-results
+	cancel()
+	n := 0
+	for range results {
+		n++
+	}
+	if n >= cap(in)-1 {
+		t.Errorf(`ToGeoStream(...) sent %d more results after cancel; wanted fewer than %d`, n, cap(in)-1)
+	}
+}
+
+func benchmarkLatLngs(n int) []LatLng {
+	latlngs := make([]LatLng, n)
+	for i := range latlngs {
+		latlngs[i] = LatLng{Lat: float64(i%1800)/10 - 89.95, Lng: float64(i%3600)/10 - 179.95}
+	}
+	return latlngs
+}
+
+func BenchmarkFromPolygon(b *testing.B) {
+	p := wavyPolygon(37.7, -122.44, 0.033, 2000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FromPolygon(p)
+	}
+}
+
+func BenchmarkFromGeo(b *testing.B) {
+	latlngs := benchmarkLatLngs(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, ll := range latlngs {
+			FromGeo(ll.Lat, ll.Lng)
+		}
+	}
+}
+
+func BenchmarkFromGeoBatch(b *testing.B) {
+	latlngs := benchmarkLatLngs(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FromGeoBatch(latlngs, 0)
+	}
+}
+
+func BenchmarkToGeo(b *testing.B) {
+	placekeys := FromGeoBatch(benchmarkLatLngs(10000), 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, pk := range placekeys {
+			ToGeo(pk)
+		}
+	}
+}
+
+func BenchmarkToGeoBatch(b *testing.B) {
+	placekeys := FromGeoBatch(benchmarkLatLngs(10000), 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ToGeoBatch(placekeys, 0)
+	}
+}
+
+func BenchmarkFromH3Int(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FromH3Int(0x8a754e64992ffff)
+	}
+}
+
+func BenchmarkAppendPlacekey(b *testing.B) {
+	b.ReportAllocs()
+	dst := make([]byte, 0, 16)
+	for i := 0; i < b.N; i++ {
+		dst = AppendPlacekey(dst[:0], 0x8a754e64992ffff)
+	}
+}
+
+func BenchmarkToH3Int(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ToH3Int("@dvt-smp-tvz")
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for lat := -89.5; lat < 90; lat += 0.73 {
+		for lon := -179.5; lon < 180; lon += 1.37 {
+			h3Int := h3Indexer.FromGeo(lat, lon, resolution)
+			pk := FromH3Int(h3Int)
+			if got := ToH3Int(pk); got != h3Int {
+				t.Fatalf(`ToH3Int(FromH3Int(%x)) = %x; wanted %x`, h3Int, got, h3Int)
+			}
+			if got := string(AppendPlacekey([]byte("x"), h3Int)); got != "x"+pk {
+				t.Fatalf(`AppendPlacekey("x", %x) = "%s"; wanted "x%s"`, h3Int, got, pk)
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package placekey
+
+import (
+	"math"
+	"strconv"
+)
+
+// SharedPrefixLength returns the number of leading characters, from 0 to 9, shared by the where
+// parts of two Placekeys, not counting "@" and "-". It returns 0 if either Placekey is invalid.
+func SharedPrefixLength(placekey1, placekey2 string) int {
+	n, _ := SharedPrefixLengthE(placekey1, placekey2)
+	return n
+}
+
+// SharedPrefixLengthE returns the number of leading characters, from 0 to 9, shared by the where
+// parts of two Placekeys, returning an error if either Placekey is invalid.
+func SharedPrefixLengthE(placekey1, placekey2 string) (int, error) {
+	h3Int1, err := ParseWhere(placekey1)
+	if err != nil {
+		return 0, err
+	}
+	h3Int2, err := ParseWhere(placekey2)
+	if err != nil {
+		return 0, err
+	}
+	return sharedPrefixLength(h3Int1, h3Int2), nil
+}
+
+// MaxDistanceForPrefix returns the maximal distance in meters between two Placekeys sharing a
+// prefix of length n, as given by GetPrefixDistanceMap. n is clamped to the range 0 to 9.
+func MaxDistanceForPrefix(n int) float64 {
+	if n < 0 {
+		n = 0
+	}
+	if n > codeLength {
+		n = codeLength
+	}
+	return GetPrefixDistanceMap()[n]
+}
+
+// GroupByPrefix groups Placekeys by the shortest shared prefix that guarantees they are within
+// maxMeters of each other, keeping the order in which groups and Placekeys first appear. If
+// maxMeters is less than the distance for a full shared prefix, every Placekey is in its own
+// group. Invalid Placekeys are left out.
+func GroupByPrefix(placekeys []string, maxMeters float64) [][]string {
+	valid := make([]string, 0, len(placekeys))
+	for _, pk := range placekeys {
+		if _, err := ParseWhere(pk); err == nil {
+			valid = append(valid, pk)
+		}
+	}
+	groups, _ := GroupByPrefixE(valid, maxMeters)
+	return groups
+}
+
+// GroupByPrefixE groups Placekeys by the shortest shared prefix that guarantees they are within
+// maxMeters of each other, returning an error if a Placekey is invalid or maxMeters is negative
+// or NaN.
+func GroupByPrefixE(placekeys []string, maxMeters float64) ([][]string, error) {
+	if math.IsNaN(maxMeters) || maxMeters < 0 {
+		return nil, &Error{Input: strconv.FormatFloat(maxMeters, 'f', -1, 64), Err: ErrInvalidDistance}
+	}
+	n := prefixLengthForDistance(maxMeters)
+
+	groups := [][]string{}
+	index := map[string]int{}
+	for _, pk := range placekeys {
+		h3Int, err := ParseWhere(pk)
+		if err != nil {
+			return nil, err
+		}
+		if n > codeLength {
+			groups = append(groups, []string{pk})
+			continue
+		}
+		code := whereCode(h3Int)
+		prefix := string(code[:n])
+		i, ok := index[prefix]
+		if !ok {
+			i = len(groups)
+			index[prefix] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], pk)
+	}
+	return groups, nil
+}
+
+// SharedPrefixLength returns the number of leading characters, from 0 to 9, shared by the where
+// parts of two Placekeys, not counting "@" and "-", or 0 if either is the zero value.
+func (pk Placekey) SharedPrefixLength(other Placekey) int {
+	if pk.IsZero() || other.IsZero() {
+		return 0
+	}
+	return sharedPrefixLength(pk.h3, other.h3)
+}
+
+///////////////////////////////////////////////////
+///////////////////////////////////////////////////
+
+func sharedPrefixLength(h3Int1, h3Int2 uint64) int {
+	code1, code2 := whereCode(h3Int1), whereCode(h3Int2)
+	n := 0
+	for n < codeLength && code1[n] == code2[n] {
+		n++
+	}
+	return n
+}
+
+// whereCode returns the characters of the encoded where part of an H3 integer, without the
+// "@" and "-" separators.
+func whereCode(h3Int uint64) [9]byte {
+	var buf [whereLength]byte
+	where := appendWhere(buf[:0], h3Int)
+
+	var code [9]byte
+	n := 0
+	for _, c := range where {
+		if c != '@' && c != '-' {
+			code[n] = c
+			n++
+		}
+	}
+	return code
+}
+
+// find the shortest prefix length whose distance is at most maxMeters, or codeLength+1 if
+// there is none.
+func prefixLengthForDistance(maxMeters float64) int {
+	distances := GetPrefixDistanceMap()
+	for n := 0; n <= codeLength; n++ {
+		if distances[n] <= maxMeters {
+			return n
+		}
+	}
+	return codeLength + 1
+}
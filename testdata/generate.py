@@ -0,0 +1,59 @@
+"""Recompute the expected outputs in golden.json with placekey-py.
+
+The inputs of each entry (lat/lon, or h3 for entries without a coordinate) are kept and every
+output is replaced with the value placekey-py returns, so that running
+
+    pip install placekey
+    python testdata/generate.py
+    go test -run TestGolden .
+
+checks placekey-go against placekey-py. New inputs are added in golden_test.go and written with
+`go test -run TestGolden -update .` before running this script, which leaves their outputs
+empty. The versions of placekey-py and h3 used are recorded in the "source" field.
+"""
+
+import json
+import os
+from importlib.metadata import version
+
+import placekey as pk
+
+PATH = os.path.join(os.path.dirname(os.path.abspath(__file__)), "golden.json")
+
+
+def main():
+    with open(PATH) as f:
+        golden = json.load(f)
+
+    for e in golden["entries"]:
+        if "lat" in e:
+            e["placekey"] = pk.geo_to_placekey(e["lat"], e["lon"])
+            e["h3"] = pk.placekey_to_h3(e["placekey"])
+        else:
+            e["placekey"] = pk.h3_to_placekey(e["h3"])
+        e["center"] = list(pk.placekey_to_geo(e["placekey"]))
+        e["boundary"] = [list(c) for c in pk.placekey_to_hex_boundary(e["placekey"])]
+
+    entries = golden["entries"]
+    golden["distances"] = [
+        {
+            "placekey1": a["placekey"],
+            "placekey2": b["placekey"],
+            "distance": pk.placekey_distance(a["placekey"], b["placekey"]),
+        }
+        for a, b in zip(entries, entries[1:])
+    ]
+
+    golden["source"] = "placekey-py %s, h3 %s" % (version("placekey"), version("h3"))
+
+    def lines(items):
+        return ",\n".join("    " + json.dumps(i, separators=(",", ":")) for i in items)
+
+    with open(PATH, "w") as f:
+        f.write('{\n  "source": ' + json.dumps(golden["source"]) + ",\n")
+        f.write('  "entries": [\n' + lines(golden["entries"]) + "\n  ],\n")
+        f.write('  "distances": [\n' + lines(golden["distances"]) + "\n  ]\n}\n")
+
+
+if __name__ == "__main__":
+    main()
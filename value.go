@@ -0,0 +1,135 @@
+package placekey
+
+import (
+	"math"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// Placekey is a parsed Placekey. It holds the address and POI encodings of the what part
+// and the H3 cell of the where part, so that conversions don't need to decode the string again.
+// The zero value is not a valid Placekey: it prints as an empty string and has no location.
+type Placekey struct {
+	what What
+	h3   uint64
+}
+
+// Parse parses a Placekey string, returning an error if its format is invalid.
+func Parse(placekey string) (Placekey, error) {
+	h3Int, err := ParseWhere(placekey)
+	if err != nil {
+		return Placekey{}, err
+	}
+	what, _ := parsePlacekey(placekey)
+	w, err := ParseWhat(what)
+	if err != nil {
+		return Placekey{}, err
+	}
+	return Placekey{what: w, h3: h3Int}, nil
+}
+
+// String returns the Placekey string, e.g. "222-227@dvt-smp-tvz", or an empty string if the
+// Placekey is the zero value.
+func (pk Placekey) String() string {
+	if pk.IsZero() {
+		return ""
+	}
+	return pk.What() + encodeH3Int(pk.h3)
+}
+
+// What returns the what part of the Placekey, e.g. "222-227", or an empty string if it has none.
+func (pk Placekey) What() string {
+	return pk.what.String()
+}
+
+// Address returns the address encoding of the what part of the Placekey, or an empty string if it has none.
+func (pk Placekey) Address() string {
+	return pk.what.Address
+}
+
+// POI returns the POI encoding of the what part of the Placekey, or an empty string if it has none.
+func (pk Placekey) POI() string {
+	return pk.what.POI
+}
+
+// WithWhat returns a copy of the Placekey with its what part replaced. The zero Placekey has no
+// where part to go with a what part, so it stays the zero value.
+func (pk Placekey) WithWhat(w What) Placekey {
+	if pk.IsZero() {
+		return pk
+	}
+	pk.what = w
+	return pk
+}
+
+// SameAddress returns whether two Placekeys share a where part and an address encoding.
+func (pk Placekey) SameAddress(other Placekey) bool {
+	return pk.h3 == other.h3 && pk.what.Address != "" && pk.what.Address == other.what.Address
+}
+
+// SamePOI returns whether two Placekeys share a where part, an address encoding and a POI encoding.
+func (pk Placekey) SamePOI(other Placekey) bool {
+	return pk.SameAddress(other) && pk.what.POI != "" && pk.what.POI == other.what.POI
+}
+
+// Where returns the where part of the Placekey, e.g. "dvt-smp-tvz", or an empty string if the
+// Placekey is the zero value.
+func (pk Placekey) Where() string {
+	if pk.IsZero() {
+		return ""
+	}
+	return strings.TrimPrefix(encodeH3Int(pk.h3), "@")
+}
+
+// H3 returns the H3 hexadecimal string of the where part of the Placekey, or an empty string if
+// the Placekey is the zero value.
+func (pk Placekey) H3() string {
+	if pk.IsZero() {
+		return ""
+	}
+	return h3Indexer.ToString(pk.h3)
+}
+
+// H3Int returns the H3 integer of the where part of the Placekey, or 0 if the Placekey is the
+// zero value.
+func (pk Placekey) H3Int() uint64 {
+	return pk.h3
+}
+
+// LatLng returns the (latitude, longitude) of the center of the Placekey, or NaNs if the
+// Placekey is the zero value.
+func (pk Placekey) LatLng() (float64, float64) {
+	if pk.IsZero() {
+		return math.NaN(), math.NaN()
+	}
+	geo := h3Indexer.ToGeo(pk.h3)
+	return geo.Lat, geo.Lng
+}
+
+// Boundary returns the Polygon boundary of the Placekey as a slice of (latitude, longitude) coordinates,
+// or nil if the Placekey is the zero value.
+func (pk Placekey) Boundary() [][]float64 {
+	if pk.IsZero() {
+		return nil
+	}
+	return latLngsToSlices(h3Indexer.ToGeoBoundary(pk.h3))
+}
+
+// Polygon returns the Polygon boundary of the Placekey as an orb.Polygon, or nil if the
+// Placekey is the zero value.
+func (pk Placekey) Polygon() orb.Polygon {
+	if pk.IsZero() {
+		return nil
+	}
+	return latLngsToOrbPolygon(h3Indexer.ToGeoBoundary(pk.h3))
+}
+
+// Distance returns the distance in meters between the centers of two Placekeys, or NaN if
+// either is the zero value.
+func (pk Placekey) Distance(other Placekey) float64 {
+	if pk.IsZero() || other.IsZero() {
+		return math.NaN()
+	}
+	return geoDistance(h3Indexer.ToGeo(pk.h3), h3Indexer.ToGeo(other.h3))
+}
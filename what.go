@@ -0,0 +1,79 @@
+package placekey
+
+import (
+	"strings"
+)
+
+// What is the what part of a Placekey, made of an address encoding and an optional POI encoding,
+// e.g. "222-227" has the address "222" and the POI "227". The zero value is an empty what part.
+type What struct {
+	Address string
+	POI     string
+}
+
+// ParseWhat splits a what part into its address and POI encodings. An empty string parses to
+// an empty what part.
+func ParseWhat(what string) (What, error) {
+	if what == "" {
+		return What{}, nil
+	}
+	if strings.Trim(what, alphabet+"-") != "" {
+		return What{}, &Error{Input: what, Err: ErrInvalidCharacter}
+	}
+	if !whatRegex.MatchString(what) {
+		return What{}, &Error{Input: what, Err: ErrInvalidWhat}
+	}
+	if i := strings.Index(what, "-"); i >= 0 {
+		return What{Address: what[:i], POI: what[i+1:]}, nil
+	}
+	return What{Address: what}, nil
+}
+
+// NewWhat builds a what part from an address encoding and an optional POI encoding.
+func NewWhat(address, poi string) (What, error) {
+	if poi != "" {
+		return ParseWhat(address + "-" + poi)
+	}
+	return ParseWhat(address)
+}
+
+// String returns the what part, e.g. "222-227", or an empty string if it is empty.
+func (w What) String() string {
+	if w.POI != "" {
+		return w.Address + "-" + w.POI
+	}
+	return w.Address
+}
+
+// IsZero returns whether the what part is empty.
+func (w What) IsZero() bool {
+	return w.Address == "" && w.POI == ""
+}
+
+// SameAddress returns whether two Placekey strings share a where part and an address encoding.
+// Invalid Placekeys never match.
+func SameAddress(placekey1, placekey2 string) bool {
+	pk1, err := Parse(placekey1)
+	if err != nil {
+		return false
+	}
+	pk2, err := Parse(placekey2)
+	if err != nil {
+		return false
+	}
+	return pk1.SameAddress(pk2)
+}
+
+// SamePOI returns whether two Placekey strings share a where part, an address encoding and a
+// POI encoding. Invalid Placekeys never match.
+func SamePOI(placekey1, placekey2 string) bool {
+	pk1, err := Parse(placekey1)
+	if err != nil {
+		return false
+	}
+	pk2, err := Parse(placekey2)
+	if err != nil {
+		return false
+	}
+	return pk1.SamePOI(pk2)
+}